@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/northasteri/Kursovik/sketch"
+)
+
+func main() {
+
+	fmt.Printf("Фильтр Блума\n")
+	var n int
+	for {
+		fmt.Print("\nВведите количество элементов(до 20 млн): ")
+		var input string
+		if _, err := fmt.Scanln(&input); err != nil {
+			fmt.Println("Некорректный ввод")
+			continue
+		}
+		// Проверяем, что строка состоит только из цифр
+		if _, err := strconv.Atoi(input); err != nil || strings.TrimSpace(input) == "" {
+			fmt.Println("Некорректный ввод")
+			continue
+		}
+		n, _ = strconv.Atoi(input)
+		if n <= 0 || n > 20_000_000 {
+			fmt.Println("Некорректный ввод")
+			continue
+		}
+		break
+	}
+	// желаемая вероятность ложного срабатывания
+	targetRate := 0.01
+
+	keys := make([]string, n)
+
+	if n < 5_000_000 {
+		for i := 0; i < n; i++ {
+			keys[i] = fmt.Sprintf("element-%d", i%300_000)
+		}
+	} else {
+		for i := 0; i < n; i++ {
+			keys[i] = fmt.Sprintf("element-%d", i%5_000_000)
+		}
+	}
+
+	// фильтр Блума
+
+	// размер битового массива и число хэш-функций подбираются по n и targetRate
+	filter, err := sketch.NewBloomFilterWithRate(n, targetRate)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("Параметры фильтра: m = %d бит, k = %d, теоретическая ошибка: %.2f%%\n",
+		filter.Size(), filter.HashCount(), filter.FalsePositiveRate(n)*100)
+
+	startB := time.Now()
+
+	// Добавляем все элементы в фильтр Блума
+	for _, key := range keys {
+		// AddString хэширует байты строки без копирования в []byte
+		filter.AddString(key)
+	}
+
+	timeB := time.Since(startB)
+
+	// наивный
+	var m1 runtime.MemStats
+	runtime.ReadMemStats(&m1)
+
+	startN := time.Now()
+	naive := make(map[string]bool)
+
+	for _, key := range keys {
+
+		naive[key] = true
+	}
+
+	timeN := time.Since(startN)
+
+	var m2 runtime.MemStats
+	runtime.ReadMemStats(&m2)
+
+	naiveMemory := m2.Alloc - m1.Alloc
+
+	// Тест: считаем ложноположительные для ключей, которых точно нет
+	falsePositives := 0
+	trueNegatives := 0
+	tests := 0
+
+	for i := 0; i < n; i++ {
+		key := fmt.Sprintf("missing-%d", i)
+		inBloom := filter.ContainsString(key)
+		inNaive := naive[key]
+
+		if inBloom && !inNaive {
+			falsePositives++
+		} else if !inBloom && !inNaive {
+			trueNegatives++
+		}
+		tests++
+	}
+	absError := float64(falsePositives)
+	relError := float64(falsePositives) / float64(tests) * 100
+
+	fmt.Printf("\nСредняя абсолютная ошибка: %.f\n", absError)
+	fmt.Printf("Средняя относительная ошибка: %.2f%%\n", relError)
+
+	fmt.Printf("Время фильтра Блума: %v\n", timeB)
+	fmt.Printf("Время naive: %v\n", timeN)
+
+	// Выводим использование памяти
+	fmt.Printf("Память naive: %d байт\n", naiveMemory)
+
+	fmt.Printf("Память фильтра Блума: %d байт\n", filter.Memory())
+
+	fmt.Printf("Заполнение фильтра: %.2f%%\n", filter.FillRatio()*100)
+	fmt.Printf("Оценка числа различных элементов: %d (реально %d)\n", filter.EstimatedCount(), len(naive))
+	fmt.Printf("Оценка текущей ошибки по заполнению: %.4f%%\n", filter.EstimateFalsePositiveRate()*100)
+
+	fmt.Println("element-1:", filter.ContainsString("element-1"))
+
+	fmt.Println("element-455000:", filter.ContainsString("element-455000"))
+
+	// масштабируемый фильтр Блума: n заранее неизвестно, начинаем со 100 тыс.
+	scalable, err := sketch.NewScalableBloomFilter(100_000, targetRate)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	startS := time.Now()
+	for _, key := range keys {
+		scalable.Add([]byte(key))
+	}
+	timeS := time.Since(startS)
+
+	falsePositives = 0
+	for i := 0; i < n; i++ {
+		if scalable.Nalich([]byte(fmt.Sprintf("missing-%d", i))) {
+			falsePositives++
+		}
+	}
+
+	fmt.Printf("\nМасштабируемый фильтр Блума (стадий: %d)\n", scalable.Stages())
+	fmt.Printf("Средняя относительная ошибка: %.2f%%\n", float64(falsePositives)/float64(n)*100)
+	fmt.Printf("Теоретическая ошибка: %.2f%%\n", scalable.FalsePositiveRate()*100)
+	fmt.Printf("Время: %v\n", timeS)
+	fmt.Printf("Память: %d байт\n", scalable.Memory())
+
+	// кукушкин фильтр на то же количество элементов
+	cuckoo, err := sketch.NewCuckooFilter(n)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	startC := time.Now()
+	failed := 0
+	for _, key := range keys {
+		// повторы не добавляем: каждая копия занимала бы отдельную ячейку
+		if cuckoo.Lookup([]byte(key)) {
+			continue
+		}
+		if err := cuckoo.Add([]byte(key)); err != nil {
+			failed++
+		}
+	}
+	timeC := time.Since(startC)
+
+	falsePositives = 0
+	for i := 0; i < n; i++ {
+		if cuckoo.Lookup([]byte(fmt.Sprintf("missing-%d", i))) {
+			falsePositives++
+		}
+	}
+
+	fmt.Printf("\nКукушкин фильтр (заполнение: %.2f%%, не поместилось: %d)\n", cuckoo.LoadFactor()*100, failed)
+	fmt.Printf("Средняя относительная ошибка: %.4f%%\n", float64(falsePositives)/float64(n)*100)
+	fmt.Printf("Время: %v\n", timeC)
+	fmt.Printf("Память: %d байт\n", cuckoo.Memory())
+
+	// фильтр с частным на то же количество элементов и ту же ошибку
+	quotient, err := sketch.NewQuotientFilterWithRate(n, targetRate)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	startQ := time.Now()
+	failed = 0
+	for _, key := range keys {
+		// повторы не добавляем, как и в кукушкин фильтр
+		if quotient.Contains([]byte(key)) {
+			continue
+		}
+		if err := quotient.Insert([]byte(key)); err != nil {
+			failed++
+		}
+	}
+	timeQ := time.Since(startQ)
+
+	falsePositives = 0
+	startQL := time.Now()
+	for i := 0; i < n; i++ {
+		if quotient.Contains([]byte(fmt.Sprintf("missing-%d", i))) {
+			falsePositives++
+		}
+	}
+	timeQL := time.Since(startQL)
+
+	fmt.Printf("\nФильтр с частным (заполнение: %.2f%%, не поместилось: %d)\n", quotient.LoadFactor()*100, failed)
+	fmt.Printf("Средняя относительная ошибка: %.4f%%\n", float64(falsePositives)/float64(n)*100)
+	fmt.Printf("Теоретическая ошибка: %.4f%%\n", quotient.FalsePositiveRate()*100)
+	fmt.Printf("Время вставки: %v, время проверки: %v\n", timeQ, timeQL)
+	fmt.Printf("Память: %d байт\n", quotient.Memory())
+}
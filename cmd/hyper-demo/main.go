@@ -53,7 +53,7 @@ func (hll *HyperLogLog) Add(s string) {
 	rho := countLeadingZeros(h2) + 1
 
 	fmt.Printf("Добавляем '%s':\n", s)
-	fmt.Printf("  hash64 = 0x%016x\n", h1<<32|h2)                 // Полный 64-битный хеш
+	fmt.Printf("  hash64 = 0x%016x\n", uint64(h1)<<32|uint64(h2)) // Полный 64-битный хеш
 	fmt.Printf("  h1 = 0x%08x (%d) %% %d = %d\n", h1, h1, m, idx) // Первый хеш и индекс
 	fmt.Printf("  h2 = 0x%08x (двоичный: %032b)\n", h2, h2)       // Второй хеш в двоичном виде
 	fmt.Printf("  ведущих нулей = %d -> rho = %d\n", countLeadingZeros(h2), rho)
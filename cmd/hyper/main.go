@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/northasteri/Kursovik/sketch"
+)
+
+func main() {
+
+	// точность p = 12: 4096 регистров, стандартная ошибка ~1.6%
+	hll, err := sketch.NewHyperLogLog(12)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Printf("HyperLogLog\n")
+	var n int
+
+	for {
+		fmt.Print("\nВведите количество элементов: ")
+		var input string
+		if _, err := fmt.Scanln(&input); err != nil {
+			fmt.Println("Некорректный ввод")
+			continue
+		}
+		// Проверяем, что строка состоит только из цифр
+		if _, err := strconv.Atoi(input); err != nil || strings.TrimSpace(input) == "" {
+			fmt.Println("Некорректный ввод")
+			continue
+		}
+		n, _ = strconv.Atoi(input)
+		if n <= 0 {
+			fmt.Println("Некорректный ввод")
+			continue
+		}
+		break
+	}
+
+	startHP := time.Now()
+	for i := 0; i < n; i++ {
+		key := strconv.Itoa(rand.Intn(n))
+		//struct{} занимает 0 байтов в памяти, bool занимает 1 байт
+		//strconv.Itoa(...) — преобразует число в строку
+		hll.Add(key)
+	}
+	timeHP := time.Since(startHP)
+
+	var m1 runtime.MemStats
+	runtime.ReadMemStats(&m1)
+
+	startNaive := time.Now()
+	seen := make(map[string]struct{})
+	for i := 0; i < n; i++ {
+		key := strconv.Itoa(rand.Intn(n))
+		//struct{} занимает 0 байтов в памяти, bool занимает 1 байт
+		//strconv.Itoa(...) — преобразует число в строку
+		seen[key] = struct{}{}
+	}
+	timeNaive := time.Since(startNaive)
+
+	var m2 runtime.MemStats
+	runtime.ReadMemStats(&m2)
+	naiveMemory := m2.Alloc - m1.Alloc
+
+	// Реальное количество уникальных элементов
+	exact := float64(len(seen))
+	// Оценка HyperLogLog
+	estimated := hll.Estimate()
+
+	// Абсолютная ошибка
+	absError := math.Abs(estimated - exact)
+	// Относительная ошибка
+	relError := absError / exact * 100
+
+	fmt.Printf("\nСредняя абсолютная ошибка: %.2f\n", absError)
+	fmt.Printf("Средняя относительная ошибка: %.2f%%\n", relError)
+	fmt.Printf("Стандартная ошибка при p = %d: %.2f%%\n", hll.Precision(), hll.StandardError()*100)
+
+	fmt.Printf("Память HyperLogLog: %d байт\n", hll.Memory())
+	fmt.Printf("Память Naive: %d байт\n", naiveMemory)
+
+	fmt.Printf("Наивный алгоритм:  %v\n", timeNaive)
+	fmt.Printf("Hyper:  %v\n", timeHP)
+
+	fmt.Printf("Реальное количество уникальных элементов: %d\n", len(seen))
+	fmt.Printf("Оценка уникальных элементов HyperLogLog: %.2f\n", hll.Estimate())
+}
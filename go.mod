@@ -0,0 +1,3 @@
+module github.com/northasteri/Kursovik
+
+go 1.23
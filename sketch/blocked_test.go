@@ -0,0 +1,98 @@
+package sketch
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBlockedBloomFilterFalsePositiveRate(t *testing.T) {
+	const n, probes = 100_000, 200_000
+	for _, p := range []float64{0.1, 0.01, 0.001} {
+		t.Run(fmt.Sprintf("p=%g", p), func(t *testing.T) {
+			bbf, err := NewBlockedBloomFilterWithRate(n, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := measureFPR(bbf.Add, bbf.Nalich, n, probes)
+			want := bbf.FalsePositiveRate(n)
+			if got > want*1.25+5.0/probes || got < want*0.75-5.0/probes {
+				t.Errorf("FPR = %.5f, теоретическая %.5f", got, want)
+			}
+
+			// при той же памяти блочный фильтр ошибается чаще обычного,
+			// и разрыв растёт с уменьшением p
+			bf := NewBloomFilter(bbf.Size(), bbf.HashCount())
+			t.Logf("p = %g: блочный %.5f, обычный %.5f (в %.2f раза)",
+				p, want, bf.FalsePositiveRate(n), want/bf.FalsePositiveRate(n))
+			if want < bf.FalsePositiveRate(n) {
+				t.Errorf("теоретическая ошибка блочного фильтра %.5f меньше обычного %.5f", want, bf.FalsePositiveRate(n))
+			}
+		})
+	}
+}
+
+func TestBlockedBloomFilterNoFalseNegatives(t *testing.T) {
+	bbf, _ := NewBlockedBloomFilterWithRate(10_000, 0.01)
+	for i := 0; i < 10_000; i++ {
+		bbf.Add([]byte(fmt.Sprintf("element-%d", i)))
+	}
+	for i := 0; i < 10_000; i++ {
+		if !bbf.Nalich([]byte(fmt.Sprintf("element-%d", i))) {
+			t.Fatalf("element-%d не найден", i)
+		}
+	}
+}
+
+// Фильтры на 20 млн элементов (~24 МБ) не помещаются в кэш процессора,
+// как в программе cmd/blum
+const benchFilterItems = 20_000_000
+
+func benchKeys() [][]byte {
+	keys := make([][]byte, 1<<20)
+	for i := range keys {
+		keys[i] = []byte(fmt.Sprintf("element-%d", i))
+	}
+	return keys
+}
+
+func BenchmarkBloomFilterAdd(b *testing.B) {
+	bf, _ := NewBloomFilterWithRate(benchFilterItems, 0.01)
+	keys := benchKeys()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bf.Add(keys[i&(len(keys)-1)])
+	}
+}
+
+func BenchmarkBlockedBloomFilterAdd(b *testing.B) {
+	bbf, _ := NewBlockedBloomFilterWithRate(benchFilterItems, 0.01)
+	keys := benchKeys()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bbf.Add(keys[i&(len(keys)-1)])
+	}
+}
+
+func BenchmarkBloomFilterNalich(b *testing.B) {
+	bf, _ := NewBloomFilterWithRate(benchFilterItems, 0.01)
+	keys := benchKeys()
+	for _, k := range keys {
+		bf.Add(k)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bf.Nalich(keys[i&(len(keys)-1)])
+	}
+}
+
+func BenchmarkBlockedBloomFilterNalich(b *testing.B) {
+	bbf, _ := NewBlockedBloomFilterWithRate(benchFilterItems, 0.01)
+	keys := benchKeys()
+	for _, k := range keys {
+		bbf.Add(k)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bbf.Nalich(keys[i&(len(keys)-1)])
+	}
+}
@@ -0,0 +1,205 @@
+package sketch
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+)
+
+// ErrInvalidParams возвращается, если параметры фильтра заданы некорректно
+var ErrInvalidParams = errors.New("sketch: некорректные параметры фильтра")
+
+type BloomFilter struct {
+	bitSet []uint64 // массив 64-битных целых чисел для хранения битов
+
+	size      int // Общий размер битового массива в битах (количество доступных битов)
+	hashCount int // Количество хэш-функций, используемых для каждого элемента (параметр k)
+
+	hasher Hasher    // хэш-функция, дающая два независимых базовых хэша
+	mode   indexMode // способ получения k индексов из двух базовых хэшей
+}
+
+func NewBloomFilter(size, hashCount int) *BloomFilter {
+
+	bitSetSize := size / 64
+	if size%64 != 0 {
+		bitSetSize++
+	}
+
+	return &BloomFilter{
+		bitSet:    make([]uint64, bitSetSize), //  массив нужного размера
+		size:      size,                       //  общий размер в битах
+		hashCount: hashCount,
+		hasher:    DefaultHasher,
+	}
+}
+
+// SetHasher задаёт хэш-функцию. Вызывать до добавления элементов
+func (bf *BloomFilter) SetHasher(h Hasher) {
+	bf.hasher = h
+}
+
+// OptimalParams вычисляет оптимальный размер битового массива m и число
+// хэш-функций k для n ожидаемых элементов и желаемой вероятности ложного
+// срабатывания p:
+//
+//	m = -n * ln(p) / (ln 2)^2
+//	k = m / n * ln 2
+func OptimalParams(n int, p float64) (size, hashCount int, err error) {
+	if n <= 0 || !(p > 0 && p < 1) {
+		return 0, 0, ErrInvalidParams
+	}
+	size = int(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	hashCount = int(math.Round(float64(size) / float64(n) * math.Ln2))
+	if hashCount < 1 {
+		hashCount = 1
+	}
+	return size, hashCount, nil
+}
+
+// NewBloomFilterWithRate создаёт фильтр, рассчитанный на n элементов
+// с вероятностью ложного срабатывания не выше p
+func NewBloomFilterWithRate(n int, p float64) (*BloomFilter, error) {
+	size, hashCount, err := OptimalParams(n, p)
+	if err != nil {
+		return nil, err
+	}
+	return NewBloomFilter(size, hashCount), nil
+}
+
+// Size возвращает размер битового массива в битах (m)
+func (bf *BloomFilter) Size() int {
+	return bf.size
+}
+
+// HashCount возвращает количество хэш-функций (k)
+func (bf *BloomFilter) HashCount() int {
+	return bf.hashCount
+}
+
+// FalsePositiveRate возвращает теоретическую вероятность ложного
+// срабатывания после добавления n различных элементов:
+//
+//	p = (1 - e^(-k*n/m))^k
+func (bf *BloomFilter) FalsePositiveRate(n int) float64 {
+	k := float64(bf.hashCount)
+	return math.Pow(1-math.Exp(-k*float64(n)/float64(bf.size)), k)
+}
+
+// FillRatio возвращает долю установленных битов X/m
+func (bf *BloomFilter) FillRatio() float64 {
+	ones := 0
+	for _, w := range bf.bitSet {
+		ones += bits.OnesCount64(w)
+	}
+	return float64(ones) / float64(bf.size)
+}
+
+// EstimatedCount оценивает количество различных добавленных элементов
+// по доле установленных битов (Swamidass, Baldi, 2007):
+//
+//	n = -m/k * ln(1 - X/m)
+//
+// Если установлены все биты, оценка бесконечна — возвращается math.MaxInt
+func (bf *BloomFilter) EstimatedCount() int {
+	fill := bf.FillRatio()
+	if fill >= 1 {
+		return math.MaxInt
+	}
+	n := -float64(bf.size) / float64(bf.hashCount) * math.Log1p(-fill)
+	return int(math.Round(n))
+}
+
+// EstimateFalsePositiveRate оценивает текущую вероятность ложного
+// срабатывания по фактической доле установленных битов: p = (X/m)^k.
+// В отличие от FalsePositiveRate не требует знать число элементов,
+// поэтому подходит и для результатов Union и Intersect
+func (bf *BloomFilter) EstimateFalsePositiveRate() float64 {
+	return math.Pow(bf.FillRatio(), float64(bf.hashCount))
+}
+
+// location возвращает индекс i-й хэш-функции в массиве из size ячеек.
+// v1 и v2 — две независимые половины 128-битного хэша элемента
+func location(v1, v2 uint64, i, size int) int {
+	// Комбинируем базовые хэши с номером итерации (Kirsch, Mitzenmacher):
+	// mixed = v1 + i*v2 даёт k различных хэш-функций из двух
+	mixed := v1 + uint64(i)*v2
+
+	// Вычисляем индекс в диапазоне [0, size-1] с помощью операции остатка от деления
+	// % size гарантирует, что индекс не выйдет за пределы массива
+	return int(mixed % uint64(size))
+}
+
+// indexMode задаёт, как из двух базовых хэшей получаются k индексов.
+// Режимы Guava нужны только для совместимости с Java (см. guava.go)
+type indexMode uint8
+
+const (
+	indexDoubleHashing indexMode = iota // location: (v1 + i*v2) mod m
+	indexGuava32                        // MURMUR128_MITZ_32
+	indexGuava64                        // MURMUR128_MITZ_64
+)
+
+// index возвращает индекс i-й хэш-функции с учётом режима фильтра
+func (bf *BloomFilter) index(v1, v2 uint64, i int) int {
+	switch bf.mode {
+	case indexGuava64:
+		// в Java сумма — знаковый long, знаковый бит сбрасывается маской
+		return int(((v1 + uint64(i)*v2) & math.MaxInt64) % uint64(bf.size))
+	case indexGuava32:
+		// используются только младшие 64 бита хэша, разбитые на два int,
+		// и номера хэш-функций идут с единицы
+		h1, h2 := int32(v1), int32(v1>>32)
+		c := h1 + int32(i+1)*h2
+		if c < 0 {
+			c = ^c
+		}
+		return int(c) % bf.size
+	}
+	return location(v1, v2, i, bf.size)
+}
+
+func (bf *BloomFilter) Add(item []byte) {
+
+	v1, v2 := bf.hasher.Sum128(item)
+
+	// hashCount различных индексов
+	for i := 0; i < bf.hashCount; i++ {
+
+		idx := bf.index(v1, v2, i)
+
+		// Устанавливаем соответствующий бит в массиве:
+		// 1. idx/64 - определяем, в каком элементе массива uint64 находится нужный бит
+		// 2. idx%64 - определяем позицию бита
+		// 3. 1 << (idx % 64) - создаём битовую маску с единицей в нужной позиции
+		// 4. |= (побитовое ИЛИ с присваиванием) - устанавливает бит в 1
+		bf.bitSet[idx/64] |= 1 << (idx % 64)
+	}
+
+}
+
+func (bf *BloomFilter) Nalich(item []byte) bool {
+
+	v1, v2 := bf.hasher.Sum128(item)
+
+	// Проверяем все hashCount битов, которые должны быть установлены для этого элемента
+	for i := 0; i < bf.hashCount; i++ {
+		// Вычисляем индекс по точно такой же формуле, как в методе Add
+		idx := bf.index(v1, v2, i)
+
+		// Проверяем, установлен ли бит по вычисленному индексу:
+		// 1. bf.bitSet[idx/64] - получаем нужный элемент массива uint64
+		// 2. &(1 << (idx % 64)) - применяем битовую маску для проверки конкретного бита
+		// 3. == 0 - если результат равен 0, значит бит НЕ установлен
+		if bf.bitSet[idx/64]&(1<<(idx%64)) == 0 {
+			// Нашли хотя бы один не установленный бит
+			return false
+		}
+	}
+	return true
+}
+
+// Memory возвращает размер битового массива в байтах
+func (bf *BloomFilter) Memory() int {
+	return len(bf.bitSet) * 8
+}
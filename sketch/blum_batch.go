@@ -0,0 +1,73 @@
+package sketch
+
+import "unsafe"
+
+// batchChunk — сколько элементов пакета хэшируется до обращения к битам.
+// Хэши 32 элементов помещаются на стеке, а независимые обращения
+// к памяти процессор выполняет параллельно, не дожидаясь каждого промаха кэша
+const batchChunk = 32
+
+// stringBytes возвращает байты строки без копирования. Хэшеры только
+// читают данные, поэтому изменять срез некому
+func stringBytes(s string) []byte {
+	return unsafe.Slice(unsafe.StringData(s), len(s))
+}
+
+// AddString добавляет строку, не выделяя память под []byte(s)
+func (bf *BloomFilter) AddString(s string) {
+	bf.Add(stringBytes(s))
+}
+
+// ContainsString проверяет строку, не выделяя память под []byte(s)
+func (bf *BloomFilter) ContainsString(s string) bool {
+	return bf.Nalich(stringBytes(s))
+}
+
+// hashChunk хэширует до batchChunk элементов
+func (bf *BloomFilter) hashChunk(items [][]byte, v1, v2 *[batchChunk]uint64) {
+	for j, item := range items {
+		v1[j], v2[j] = bf.hasher.Sum128(item)
+	}
+}
+
+// AddBatch добавляет элементы пакетами: сначала считаются хэши пакета,
+// затем устанавливаются биты
+func (bf *BloomFilter) AddBatch(items [][]byte) {
+	var v1, v2 [batchChunk]uint64
+	for len(items) > 0 {
+		chunk := items[:min(len(items), batchChunk)]
+		items = items[len(chunk):]
+		bf.hashChunk(chunk, &v1, &v2)
+		for j := range chunk {
+			for i := 0; i < bf.hashCount; i++ {
+				idx := bf.index(v1[j], v2[j], i)
+				bf.bitSet[idx/64] |= 1 << (idx % 64)
+			}
+		}
+	}
+}
+
+// ContainsBatch проверяет элементы пакетами и записывает результат
+// для items[i] в out[i]. out должен быть не короче items.
+// Возвращает количество найденных элементов
+func (bf *BloomFilter) ContainsBatch(items [][]byte, out []bool) int {
+	out = out[:len(items)]
+	found := 0
+	var v1, v2 [batchChunk]uint64
+	for base := 0; base < len(items); base += batchChunk {
+		chunk := items[base:min(len(items), base+batchChunk)]
+		bf.hashChunk(chunk, &v1, &v2)
+		for j := range chunk {
+			ok := true
+			for i := 0; i < bf.hashCount && ok; i++ {
+				idx := bf.index(v1[j], v2[j], i)
+				ok = bf.bitSet[idx/64]&(1<<(idx%64)) != 0
+			}
+			out[base+j] = ok
+			if ok {
+				found++
+			}
+		}
+	}
+	return found
+}
@@ -0,0 +1,114 @@
+package sketch
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBatchMatchesSingle(t *testing.T) {
+	keys := make([][]byte, 1000)
+	for i := range keys {
+		keys[i] = []byte(fmt.Sprintf("element-%d", i))
+	}
+	single, _ := NewBloomFilterWithRate(len(keys), 0.01)
+	batch, _ := NewBloomFilterWithRate(len(keys), 0.01)
+	for _, k := range keys[:500] {
+		single.AddString(string(k))
+	}
+	batch.AddBatch(keys[:500])
+	for i := range single.bitSet {
+		if single.bitSet[i] != batch.bitSet[i] {
+			t.Fatalf("слово %d: AddString %#x, AddBatch %#x", i, single.bitSet[i], batch.bitSet[i])
+		}
+	}
+
+	out := make([]bool, len(keys))
+	found := batch.ContainsBatch(keys, out)
+	want := 0
+	for i, k := range keys {
+		if out[i] != single.ContainsString(string(k)) {
+			t.Fatalf("ContainsBatch[%d] = %v, ContainsString = %v", i, out[i], !out[i])
+		}
+		if out[i] {
+			want++
+		}
+	}
+	if found != want || found < 500 {
+		t.Errorf("ContainsBatch нашёл %d, want %d (не меньше 500)", found, want)
+	}
+}
+
+func TestBatchNoAllocs(t *testing.T) {
+	bf, _ := NewBloomFilterWithRate(10_000, 0.01)
+	keys := benchKeys()[:100]
+	out := make([]bool, len(keys))
+	s := "element-1"
+	for name, f := range map[string]func(){
+		"AddString":      func() { bf.AddString(s) },
+		"ContainsString": func() { bf.ContainsString(s) },
+		"AddBatch":       func() { bf.AddBatch(keys) },
+		"ContainsBatch":  func() { bf.ContainsBatch(keys, out) },
+	} {
+		if n := testing.AllocsPerRun(100, f); n != 0 {
+			t.Errorf("%s: %v выделений на вызов, want 0", name, n)
+		}
+	}
+}
+
+func benchStrings() []string {
+	keys := make([]string, 1<<20)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("element-%d", i)
+	}
+	return keys
+}
+
+func BenchmarkBloomFilterAddString(b *testing.B) {
+	bf, _ := NewBloomFilterWithRate(benchFilterItems, 0.01)
+	keys := benchStrings()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bf.AddString(keys[i&(len(keys)-1)])
+	}
+}
+
+func BenchmarkBloomFilterContainsString(b *testing.B) {
+	bf, _ := NewBloomFilterWithRate(benchFilterItems, 0.01)
+	keys := benchStrings()
+	for _, k := range keys {
+		bf.AddString(k)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bf.ContainsString(keys[i&(len(keys)-1)])
+	}
+}
+
+// пакетные бенчмарки считают время на один элемент
+const benchBatch = 1024
+
+func BenchmarkBloomFilterAddBatch(b *testing.B) {
+	bf, _ := NewBloomFilterWithRate(benchFilterItems, 0.01)
+	keys := benchKeys()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i += benchBatch {
+		off := i & (len(keys) - 1)
+		bf.AddBatch(keys[off : off+min(benchBatch, b.N-i)])
+	}
+}
+
+func BenchmarkBloomFilterContainsBatch(b *testing.B) {
+	bf, _ := NewBloomFilterWithRate(benchFilterItems, 0.01)
+	keys := benchKeys()
+	bf.AddBatch(keys)
+	out := make([]bool, benchBatch)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i += benchBatch {
+		off := i & (len(keys) - 1)
+		bf.ContainsBatch(keys[off:off+min(benchBatch, b.N-i)], out)
+	}
+}
@@ -0,0 +1,189 @@
+package sketch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// Формат файла фильтра Блума (все числа little-endian):
+//
+//	[0:4]   магическое число "BLMF"
+//	[4]     версия формата
+//	[5]     идентификатор схемы хэширования
+//	[6]     режим индексов (0 — двойное хэширование, иначе режим Guava);
+//	        в версии 1 зарезервирован и равен нулю
+//	[7]     зарезервировано (ноль)
+//	[8:12]  hashCount
+//	[12:20] size (в битах)
+//	[20:..] bitSet, len(bitSet) слов по 8 байт
+//	[..+4]  CRC-32C всех предыдущих байт
+const (
+	bloomMagic = "BLMF"
+	// фильтры с двойным хэшированием пишутся версией 1, чтобы их читали
+	// и старые версии пакета; режимы Guava — версией 2, которую старые
+	// версии отвергают как неподдерживаемую, а не как повреждённую
+	bloomFormatVersion      = 1
+	bloomFormatVersionModes = 2
+	bloomHeaderSize         = 20
+)
+
+var (
+	ErrBadMagic          = errors.New("sketch: неизвестный формат файла")
+	ErrUnsupportedFormat = errors.New("sketch: неподдерживаемая версия формата")
+	ErrUnknownHashScheme = errors.New("sketch: неизвестная схема хэширования")
+	ErrChecksum          = errors.New("sketch: контрольная сумма не совпадает")
+	ErrCorrupt           = errors.New("sketch: повреждённые данные фильтра")
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// MarshalBinary реализует encoding.BinaryMarshaler
+func (bf *BloomFilter) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(bloomHeaderSize + len(bf.bitSet)*8 + 4)
+	if _, err := bf.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary реализует encoding.BinaryUnmarshaler
+func (bf *BloomFilter) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	if _, err := bf.ReadFrom(r); err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("%w: %d лишних байт в конце", ErrCorrupt, r.Len())
+	}
+	return nil
+}
+
+// WriteTo реализует io.WriterTo
+func (bf *BloomFilter) WriteTo(w io.Writer) (int64, error) {
+	scheme, ok := hashSchemeOf(bf.hasher)
+	if !ok {
+		return 0, fmt.Errorf("%w: пользовательский Hasher нельзя сохранить", ErrUnknownHashScheme)
+	}
+
+	crc := crc32.New(castagnoli)
+	mw := io.MultiWriter(w, crc)
+
+	var header [bloomHeaderSize]byte
+	copy(header[0:4], bloomMagic)
+	header[4] = bloomFormatVersion
+	if bf.mode != indexDoubleHashing {
+		header[4] = bloomFormatVersionModes
+	}
+	header[5] = scheme
+	header[6] = byte(bf.mode)
+	binary.LittleEndian.PutUint32(header[8:12], uint32(bf.hashCount))
+	binary.LittleEndian.PutUint64(header[12:20], uint64(bf.size))
+
+	n, err := mw.Write(header[:])
+	total := int64(n)
+	if err != nil {
+		return total, err
+	}
+
+	// пишем слова блоками, чтобы не копировать весь массив разом
+	var chunk [512 * 8]byte
+	for i := 0; i < len(bf.bitSet); {
+		j := 0
+		for ; j < len(chunk) && i < len(bf.bitSet); j, i = j+8, i+1 {
+			binary.LittleEndian.PutUint64(chunk[j:], bf.bitSet[i])
+		}
+		n, err = mw.Write(chunk[:j])
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+
+	var sum [4]byte
+	binary.LittleEndian.PutUint32(sum[:], crc.Sum32())
+	n, err = w.Write(sum[:])
+	total += int64(n)
+	return total, err
+}
+
+// ReadFrom реализует io.ReaderFrom. При ошибке фильтр не изменяется
+func (bf *BloomFilter) ReadFrom(r io.Reader) (int64, error) {
+	crc := crc32.New(castagnoli)
+	tr := io.TeeReader(r, crc)
+
+	var header [bloomHeaderSize]byte
+	n, err := io.ReadFull(tr, header[:])
+	total := int64(n)
+	if err != nil {
+		return total, truncated(err)
+	}
+	if string(header[0:4]) != bloomMagic {
+		return total, ErrBadMagic
+	}
+	if header[4] != bloomFormatVersion && header[4] != bloomFormatVersionModes {
+		return total, fmt.Errorf("%w: %d", ErrUnsupportedFormat, header[4])
+	}
+	if header[5] == hashSchemeLegacyFNV {
+		return total, fmt.Errorf("%w: схема %d (FNV-1a с h1 == h2) больше не поддерживается, фильтр нужно построить заново",
+			ErrUnknownHashScheme, header[5])
+	}
+	hasher, ok := hasherOf(header[5])
+	if !ok {
+		return total, fmt.Errorf("%w: %d", ErrUnknownHashScheme, header[5])
+	}
+	hashCount := binary.LittleEndian.Uint32(header[8:12])
+	size := binary.LittleEndian.Uint64(header[12:20])
+	mode := indexMode(header[6])
+	if header[4] == bloomFormatVersion && mode != indexDoubleHashing {
+		return total, ErrCorrupt
+	}
+	if mode > indexGuava64 || header[7] != 0 || hashCount == 0 || size == 0 || size > 1<<62 {
+		return total, ErrCorrupt
+	}
+
+	// буфер растёт по мере чтения, поэтому обрезанный файл с огромным size
+	// не приведёт к выделению памяти под весь массив
+	words := (size + 63) / 64
+	var body bytes.Buffer
+	m, err := io.CopyN(&body, tr, int64(words*8))
+	total += m
+	if err != nil {
+		return total, truncated(err)
+	}
+
+	var sum [4]byte
+	n, err = io.ReadFull(r, sum[:])
+	total += int64(n)
+	if err != nil {
+		return total, truncated(err)
+	}
+	if binary.LittleEndian.Uint32(sum[:]) != crc.Sum32() {
+		return total, ErrChecksum
+	}
+
+	raw := body.Bytes()
+	bitSet := make([]uint64, words)
+	for i := range bitSet {
+		bitSet[i] = binary.LittleEndian.Uint64(raw[i*8:])
+	}
+
+	bf.bitSet = bitSet
+	bf.size = int(size)
+	bf.hashCount = int(hashCount)
+	bf.hasher = hasher
+	bf.mode = mode
+	return total, nil
+}
+
+// truncated превращает EOF посреди данных в ErrCorrupt
+func truncated(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: данные обрезаны", ErrCorrupt)
+	}
+	return err
+}
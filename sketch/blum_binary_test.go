@@ -0,0 +1,157 @@
+package sketch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// filledBloomFilter возвращает фильтр с n элементами
+func filledBloomFilter(t *testing.T, n int, h Hasher) *BloomFilter {
+	t.Helper()
+	bf, err := NewBloomFilterWithRate(n, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bf.SetHasher(h)
+	for i := 0; i < n; i++ {
+		bf.Add([]byte(fmt.Sprintf("element-%d", i)))
+	}
+	return bf
+}
+
+func sameBloomFilter(a, b *BloomFilter) bool {
+	return a.size == b.size && a.hashCount == b.hashCount && a.mode == b.mode &&
+		sameHasher(a.hasher, b.hasher) && slices.Equal(a.bitSet, b.bitSet)
+}
+
+func TestBloomFilterBinaryRoundTrip(t *testing.T) {
+	for _, h := range []Hasher{FNV, XXHash, Murmur3} {
+		bf := filledBloomFilter(t, 1000, h)
+
+		data, err := bf.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var back BloomFilter
+		if err := back.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if !sameBloomFilter(bf, &back) {
+			t.Errorf("%T: фильтр после UnmarshalBinary отличается", h)
+		}
+
+		var buf bytes.Buffer
+		n, err := bf.WriteTo(&buf)
+		if err != nil || n != int64(len(data)) || !bytes.Equal(buf.Bytes(), data) {
+			t.Fatalf("%T: WriteTo = %d, %v; образ не совпадает с MarshalBinary", h, n, err)
+		}
+		var read BloomFilter
+		if n, err := read.ReadFrom(&buf); err != nil || n != int64(len(data)) {
+			t.Fatalf("%T: ReadFrom = %d, %v", h, n, err)
+		}
+		if !sameBloomFilter(bf, &read) {
+			t.Errorf("%T: фильтр после ReadFrom отличается", h)
+		}
+		for i := 0; i < 1000; i++ {
+			if !read.Nalich([]byte(fmt.Sprintf("element-%d", i))) {
+				t.Fatalf("%T: ложноотрицательный ответ после чтения", h)
+			}
+		}
+	}
+}
+
+func TestBloomFilterBinaryErrors(t *testing.T) {
+	data, err := filledBloomFilter(t, 100, Murmur3).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	with := func(i int, b byte) []byte {
+		d := slices.Clone(data)
+		d[i] = b
+		return d
+	}
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"магическое число", with(0, 'X'), ErrBadMagic},
+		{"версия", with(4, 99), ErrUnsupportedFormat},
+		{"схема FNV h1 == h2", with(5, hashSchemeLegacyFNV), ErrUnknownHashScheme},
+		{"неизвестная схема", with(5, 200), ErrUnknownHashScheme},
+		{"испорченный бит", with(bloomHeaderSize+3, data[bloomHeaderSize+3]^0x10), ErrChecksum},
+		{"испорченная сумма", with(len(data)-1, data[len(data)-1]^1), ErrChecksum},
+		{"обрезан заголовок", data[:10], ErrCorrupt},
+		{"обрезаны биты", data[:bloomHeaderSize+5], ErrCorrupt},
+		{"обрезана сумма", data[:len(data)-2], ErrCorrupt},
+		{"лишние байты", append(slices.Clone(data), 0), ErrCorrupt},
+		{"пусто", nil, ErrCorrupt},
+	}
+	for _, tt := range tests {
+		var bf BloomFilter
+		if err := bf.UnmarshalBinary(tt.data); !errors.Is(err, tt.want) {
+			t.Errorf("%s: %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestBloomFilterReadFromFailureKeepsFilter(t *testing.T) {
+	bf := filledBloomFilter(t, 500, XXHash)
+	before := bf.clone()
+
+	other, err := filledBloomFilter(t, 2000, Murmur3).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	broken := slices.Clone(other)
+	broken[bloomHeaderSize] ^= 1
+	for _, data := range [][]byte{broken, other[:len(other)/2]} {
+		if _, err := bf.ReadFrom(bytes.NewReader(data)); err == nil {
+			t.Fatal("ReadFrom принял повреждённые данные")
+		}
+		if !sameBloomFilter(bf, before) {
+			t.Fatal("фильтр изменился после неудачного ReadFrom")
+		}
+	}
+}
+
+func TestBloomFilterBinaryVersion(t *testing.T) {
+	plain, err := filledBloomFilter(t, 100, Murmur3).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain[4] != bloomFormatVersion {
+		t.Errorf("фильтр с двойным хэшированием записан версией %d", plain[4])
+	}
+
+	// режим Guava не понимают читатели версии 1, поэтому он пишется версией 2
+	gf, err := NewGuavaBloomFilter(100, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gf.Add([]byte("element"))
+	data, err := gf.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data[4] != bloomFormatVersionModes {
+		t.Fatalf("фильтр в режиме Guava записан версией %d", data[4])
+	}
+	var back BloomFilter
+	if err := back.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !sameBloomFilter(gf, &back) || !back.Nalich([]byte("element")) {
+		t.Error("фильтр в режиме Guava после UnmarshalBinary отличается")
+	}
+
+	// в версии 1 байт режима зарезервирован
+	v1 := slices.Clone(data)
+	v1[4] = bloomFormatVersion
+	if err := back.UnmarshalBinary(v1); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("версия 1 с режимом Guava: %v, want ErrCorrupt", err)
+	}
+}
@@ -0,0 +1,104 @@
+package sketch
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// IncompatibleError возвращается при объединении или пересечении фильтров
+// с разными параметрами: такие битовые массивы нельзя складывать побитово
+type IncompatibleError struct {
+	Size, OtherSize           int
+	HashCount, OtherHashCount int
+	SameHasher                bool
+}
+
+func (e *IncompatibleError) Error() string {
+	switch {
+	case e.Size != e.OtherSize:
+		return fmt.Sprintf("sketch: фильтры несовместимы: размер %d и %d бит", e.Size, e.OtherSize)
+	case e.HashCount != e.OtherHashCount:
+		return fmt.Sprintf("sketch: фильтры несовместимы: %d и %d хэш-функций", e.HashCount, e.OtherHashCount)
+	default:
+		return "sketch: фильтры несовместимы: разные хэш-функции"
+	}
+}
+
+// sameHasher сравнивает хэшеры, не паникуя на несравнимых типах
+func sameHasher(a, b Hasher) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	if !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// compatible проверяет, что other можно объединять с bf
+func (bf *BloomFilter) compatible(other *BloomFilter) error {
+	// разные режимы индексов дают разные хэш-функции при том же Hasher
+	same := sameHasher(bf.hasher, other.hasher) && bf.mode == other.mode
+	if bf.size != other.size || bf.hashCount != other.hashCount || !same {
+		return &IncompatibleError{
+			Size:           bf.size,
+			OtherSize:      other.size,
+			HashCount:      bf.hashCount,
+			OtherHashCount: other.hashCount,
+			SameHasher:     same,
+		}
+	}
+	return nil
+}
+
+// clone возвращает независимую копию фильтра
+func (bf *BloomFilter) clone() *BloomFilter {
+	c := *bf
+	c.bitSet = append([]uint64(nil), bf.bitSet...)
+	return &c
+}
+
+// UnionWith добавляет в bf все элементы other (побитовое ИЛИ).
+// Результат совпадает с фильтром, в который добавили элементы обоих
+func (bf *BloomFilter) UnionWith(other *BloomFilter) error {
+	if err := bf.compatible(other); err != nil {
+		return err
+	}
+	for i, w := range other.bitSet {
+		bf.bitSet[i] |= w
+	}
+	return nil
+}
+
+// IntersectWith оставляет в bf только биты, установленные и в other
+// (побитовое И). Элементы пересечения всегда находятся, но ложных
+// срабатываний больше, чем у фильтра, построенного по пересечению
+func (bf *BloomFilter) IntersectWith(other *BloomFilter) error {
+	if err := bf.compatible(other); err != nil {
+		return err
+	}
+	for i, w := range other.bitSet {
+		bf.bitSet[i] &= w
+	}
+	return nil
+}
+
+// Union возвращает новый фильтр — объединение bf и other
+func (bf *BloomFilter) Union(other *BloomFilter) (*BloomFilter, error) {
+	if err := bf.compatible(other); err != nil {
+		return nil, err
+	}
+	res := bf.clone()
+	res.UnionWith(other)
+	return res, nil
+}
+
+// Intersect возвращает новый фильтр — пересечение bf и other
+func (bf *BloomFilter) Intersect(other *BloomFilter) (*BloomFilter, error) {
+	if err := bf.compatible(other); err != nil {
+		return nil, err
+	}
+	res := bf.clone()
+	res.IntersectWith(other)
+	return res, nil
+}
@@ -0,0 +1,106 @@
+package sketch
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// bloomWith возвращает фильтр size x k с элементами prefix-from..prefix-(to-1)
+func bloomWith(size, k int, prefix string, from, to int) *BloomFilter {
+	bf := NewBloomFilter(size, k)
+	for i := from; i < to; i++ {
+		bf.Add([]byte(fmt.Sprintf("%s-%d", prefix, i)))
+	}
+	return bf
+}
+
+func TestBloomFilterUnionMatchesDirect(t *testing.T) {
+	a := bloomWith(1<<16, 5, "element", 0, 3000)
+	b := bloomWith(1<<16, 5, "element", 2000, 5000)
+	direct := bloomWith(1<<16, 5, "element", 0, 5000)
+	if err := a.UnionWith(b); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(a.bitSet, direct.bitSet) {
+		t.Error("UnionWith дал не те биты, что добавление обоих наборов")
+	}
+}
+
+func TestBloomFilterUnionIntersectKeepReceiver(t *testing.T) {
+	a := bloomWith(1<<16, 5, "element", 0, 3000)
+	b := bloomWith(1<<16, 5, "element", 2000, 5000)
+	aBefore, bBefore := a.clone(), b.clone()
+
+	u, err := a.Union(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in, err := a.Intersect(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameBloomFilter(a, aBefore) || !sameBloomFilter(b, bBefore) {
+		t.Fatal("Union или Intersect изменили аргументы")
+	}
+	for i := 0; i < 5000; i++ {
+		item := []byte(fmt.Sprintf("element-%d", i))
+		if !u.Nalich(item) {
+			t.Fatalf("объединение не содержит element-%d", i)
+		}
+		if i >= 2000 && i < 3000 && !in.Nalich(item) {
+			t.Fatalf("пересечение не содержит element-%d", i)
+		}
+	}
+	for i, w := range in.bitSet {
+		if w != a.bitSet[i]&b.bitSet[i] || u.bitSet[i] != a.bitSet[i]|b.bitSet[i] {
+			t.Fatalf("слово %d: биты не совпадают с побитовыми операциями", i)
+		}
+	}
+}
+
+func TestBloomFilterIncompatible(t *testing.T) {
+	base := NewBloomFilter(1024, 4)
+	xx := NewBloomFilter(1024, 4)
+	xx.SetHasher(XXHash)
+	guava := NewBloomFilter(1024, 4)
+	guava.mode = indexGuava64
+
+	tests := []struct {
+		name  string
+		other *BloomFilter
+		check func(*IncompatibleError) bool
+		msg   string
+	}{
+		{"размер", NewBloomFilter(2048, 4), func(e *IncompatibleError) bool {
+			return e.Size == 1024 && e.OtherSize == 2048
+		}, "размер"},
+		{"число хэш-функций", NewBloomFilter(1024, 5), func(e *IncompatibleError) bool {
+			return e.HashCount == 4 && e.OtherHashCount == 5 && e.SameHasher
+		}, "хэш-функций"},
+		{"хэшер", xx, func(e *IncompatibleError) bool { return !e.SameHasher }, "разные"},
+		{"режим индексов", guava, func(e *IncompatibleError) bool { return !e.SameHasher }, "разные"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := map[string]func() error{
+				"UnionWith":     func() error { return base.clone().UnionWith(tt.other) },
+				"IntersectWith": func() error { return base.clone().IntersectWith(tt.other) },
+				"Union":         func() error { _, err := base.Union(tt.other); return err },
+				"Intersect":     func() error { _, err := base.Intersect(tt.other); return err },
+			}
+			for name, op := range ops {
+				var ie *IncompatibleError
+				err := op()
+				if !errors.As(err, &ie) {
+					t.Fatalf("%s: %v, want *IncompatibleError", name, err)
+				}
+				if !tt.check(ie) || !strings.Contains(ie.Error(), tt.msg) {
+					t.Errorf("%s: %+v, %q", name, *ie, ie.Error())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package sketch
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// measureFPR заполняет фильтр n элементами и возвращает долю ложных
+// срабатываний на probes отсутствующих ключах
+func measureFPR(add func([]byte), nalich func([]byte) bool, n, probes int) float64 {
+	for i := 0; i < n; i++ {
+		add([]byte(fmt.Sprintf("element-%d", i)))
+	}
+	fp := 0
+	for i := 0; i < probes; i++ {
+		if nalich([]byte(fmt.Sprintf("missing-%d", i))) {
+			fp++
+		}
+	}
+	return float64(fp) / float64(probes)
+}
+
+func TestBloomFilterFalsePositiveRate(t *testing.T) {
+	const n, probes = 100_000, 200_000
+	for _, tt := range []struct {
+		name   string
+		hasher Hasher
+	}{
+		{"FNV", FNV},
+		{"XXHash", XXHash},
+		{"Murmur3", Murmur3},
+	} {
+		for _, p := range []float64{0.1, 0.01, 0.001} {
+			t.Run(fmt.Sprintf("%s/p=%g", tt.name, p), func(t *testing.T) {
+				bf, err := NewBloomFilterWithRate(n, p)
+				if err != nil {
+					t.Fatal(err)
+				}
+				bf.SetHasher(tt.hasher)
+
+				got := measureFPR(bf.Add, bf.Nalich, n, probes)
+				want := bf.FalsePositiveRate(n)
+				// допускаем отклонение в 25% и запас на малые вероятности
+				if got > want*1.25+5.0/probes || got < want*0.75-5.0/probes {
+					t.Errorf("FPR = %.5f, теоретическая %.5f", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestBloomFilterNoFalseNegatives(t *testing.T) {
+	bf, _ := NewBloomFilterWithRate(10_000, 0.01)
+	for i := 0; i < 10_000; i++ {
+		bf.Add([]byte(fmt.Sprintf("element-%d", i)))
+	}
+	for i := 0; i < 10_000; i++ {
+		if !bf.Nalich([]byte(fmt.Sprintf("element-%d", i))) {
+			t.Fatalf("element-%d не найден", i)
+		}
+	}
+}
+
+func TestCountMinSketchCollisions(t *testing.T) {
+	// при ширине e/eps ошибка оценки не больше eps*N с вероятностью 1 - e^-depth
+	const width, depth, n = 2719, 5, 100_000 // eps = 0.001
+	cms := CountMinSketch(width, depth)
+	cms.Init()
+	for i := 0; i < n; i++ {
+		cms.Add(fmt.Sprintf("element-%d", i%10_000), 1)
+	}
+	bad := 0
+	for i := 0; i < 10_000; i++ {
+		if cms.Count(fmt.Sprintf("element-%d", i))-10 > n/1000 {
+			bad++
+		}
+	}
+	if bad > 10_000/100 {
+		t.Errorf("%d из 10000 оценок превышают границу eps*N", bad)
+	}
+}
+
+func TestBloomFilterEstimatedCount(t *testing.T) {
+	bf, err := NewBloomFilterWithRate(50_000, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	added := 0
+	for _, n := range []int{1000, 10_000, 50_000, 80_000} {
+		for ; added < n; added++ {
+			bf.Add([]byte(fmt.Sprintf("element-%d", added)))
+		}
+		if got := bf.EstimatedCount(); math.Abs(float64(got-n)) > 0.03*float64(n) {
+			t.Errorf("n = %d: EstimatedCount = %d", n, got)
+		}
+		// оценка ошибки по заполнению близка к теоретической для n элементов
+		if est, want := bf.EstimateFalsePositiveRate(), bf.FalsePositiveRate(n); math.Abs(est-want) > 0.1*want {
+			t.Errorf("n = %d: EstimateFalsePositiveRate = %.5f, want около %.5f", n, est, want)
+		}
+	}
+
+	full := NewBloomFilter(64, 3)
+	for i := 0; full.FillRatio() < 1; i++ {
+		full.Add([]byte(fmt.Sprintf("element-%d", i)))
+	}
+	if got := full.EstimatedCount(); got != math.MaxInt {
+		t.Errorf("EstimatedCount насыщенного фильтра = %d, want math.MaxInt", got)
+	}
+	if got := full.EstimateFalsePositiveRate(); got != 1 {
+		t.Errorf("EstimateFalsePositiveRate насыщенного фильтра = %v", got)
+	}
+}
@@ -0,0 +1,67 @@
+package sketch
+
+import "sync/atomic"
+
+// ConcurrentBloomFilter — фильтр Блума, который можно одновременно
+// заполнять и проверять из многих горутин без блокировок: слова битового
+// массива меняются атомарным ИЛИ и читаются атомарной загрузкой
+type ConcurrentBloomFilter struct {
+	bitSet []uint64
+
+	size      int
+	hashCount int
+	hasher    Hasher
+}
+
+func NewConcurrentBloomFilter(size, hashCount int) *ConcurrentBloomFilter {
+	return &ConcurrentBloomFilter{
+		bitSet:    make([]uint64, (size+63)/64),
+		size:      size,
+		hashCount: hashCount,
+		hasher:    DefaultHasher,
+	}
+}
+
+// NewConcurrentBloomFilterWithRate подбирает размер и число хэш-функций
+// так же, как NewBloomFilterWithRate
+func NewConcurrentBloomFilterWithRate(n int, p float64) (*ConcurrentBloomFilter, error) {
+	size, hashCount, err := OptimalParams(n, p)
+	if err != nil {
+		return nil, err
+	}
+	return NewConcurrentBloomFilter(size, hashCount), nil
+}
+
+// SetHasher задаёт хэш-функцию. Вызывать до начала работы с фильтром,
+// сам вызов не потокобезопасен
+func (bf *ConcurrentBloomFilter) SetHasher(h Hasher) {
+	bf.hasher = h
+}
+
+func (bf *ConcurrentBloomFilter) Add(item []byte) {
+	v1, v2 := bf.hasher.Sum128(item)
+	for i := 0; i < bf.hashCount; i++ {
+		idx := location(v1, v2, i, bf.size)
+		mask := uint64(1) << (idx % 64)
+		// не пишем в уже установленный бит, чтобы не гонять кэш-линию между ядрами
+		if atomic.LoadUint64(&bf.bitSet[idx/64])&mask == 0 {
+			atomic.OrUint64(&bf.bitSet[idx/64], mask)
+		}
+	}
+}
+
+func (bf *ConcurrentBloomFilter) Nalich(item []byte) bool {
+	v1, v2 := bf.hasher.Sum128(item)
+	for i := 0; i < bf.hashCount; i++ {
+		idx := location(v1, v2, i, bf.size)
+		if atomic.LoadUint64(&bf.bitSet[idx/64])&(1<<(idx%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Memory возвращает размер битового массива в байтах
+func (bf *ConcurrentBloomFilter) Memory() int {
+	return len(bf.bitSet) * 8
+}
@@ -0,0 +1,109 @@
+package sketch
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentBloomFilterNoLostBits(t *testing.T) {
+	const workers, perWorker = 8, 20_000
+	bf, err := NewConcurrentBloomFilterWithRate(workers*perWorker, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				key := []byte(fmt.Sprintf("element-%d-%d", w, i))
+				bf.Add(key)
+				// читаем параллельно с чужими записями
+				bf.Nalich([]byte(fmt.Sprintf("element-%d-%d", (w+1)%workers, i)))
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	for w := 0; w < workers; w++ {
+		for i := 0; i < perWorker; i++ {
+			if !bf.Nalich([]byte(fmt.Sprintf("element-%d-%d", w, i))) {
+				t.Fatalf("element-%d-%d потерян", w, i)
+			}
+		}
+	}
+}
+
+func TestConcurrentBloomFilterMatchesBloomFilter(t *testing.T) {
+	cbf := NewConcurrentBloomFilter(10_000, 5)
+	bf := NewBloomFilter(10_000, 5)
+	for i := 0; i < 1000; i++ {
+		key := []byte(strconv.Itoa(i))
+		cbf.Add(key)
+		bf.Add(key)
+	}
+	for i := range bf.bitSet {
+		if bf.bitSet[i] != cbf.bitSet[i] {
+			t.Fatalf("слово %d: %#x != %#x", i, cbf.bitSet[i], bf.bitSet[i])
+		}
+	}
+}
+
+// mutexBloomFilter — обычный фильтр под мьютексом, база для сравнения
+type mutexBloomFilter struct {
+	mu sync.RWMutex
+	bf *BloomFilter
+}
+
+func (m *mutexBloomFilter) Add(item []byte) {
+	m.mu.Lock()
+	m.bf.Add(item)
+	m.mu.Unlock()
+}
+
+func (m *mutexBloomFilter) Nalich(item []byte) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.bf.Nalich(item)
+}
+
+func benchmarkParallel(b *testing.B, op func([]byte)) {
+	keys := make([][]byte, 1<<16)
+	for i := range keys {
+		keys[i] = []byte(fmt.Sprintf("element-%d", i))
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			op(keys[i&(len(keys)-1)])
+			i++
+		}
+	})
+}
+
+func BenchmarkConcurrentBloomFilterAdd(b *testing.B) {
+	bf, _ := NewConcurrentBloomFilterWithRate(1_000_000, 0.01)
+	benchmarkParallel(b, bf.Add)
+}
+
+func BenchmarkMutexBloomFilterAdd(b *testing.B) {
+	bf, _ := NewBloomFilterWithRate(1_000_000, 0.01)
+	m := &mutexBloomFilter{bf: bf}
+	benchmarkParallel(b, m.Add)
+}
+
+func BenchmarkConcurrentBloomFilterNalich(b *testing.B) {
+	bf, _ := NewConcurrentBloomFilterWithRate(1_000_000, 0.01)
+	benchmarkParallel(b, func(item []byte) { bf.Nalich(item) })
+}
+
+func BenchmarkMutexBloomFilterNalich(b *testing.B) {
+	bf, _ := NewBloomFilterWithRate(1_000_000, 0.01)
+	m := &mutexBloomFilter{bf: bf}
+	benchmarkParallel(b, func(item []byte) { m.Nalich(item) })
+}
@@ -0,0 +1,70 @@
+package sketch
+
+// math — для математических констант и функций
+import "math"
+
+type Sketch struct {
+	width  int      // ширина таблицы (количество столбцов)
+	depth  int      // глубина таблицы (количество строк, хэш-функций)
+	table  [][]int  // двумерный массив счётчиков [depth][width]
+	hashes []uint64 // массив для каждой хэш-функции
+	hasher Hasher   // базовая 128-битная хэш-функция
+}
+
+func CountMinSketch(width, depth int) *Sketch {
+	return &Sketch{
+		width:  width,                 // инициализация ширины
+		depth:  depth,                 // инициализация глубины
+		table:  make([][]int, depth),  // выделение памяти для строк таблицы
+		hashes: make([]uint64, depth), // выделение памяти для массива соль
+		hasher: DefaultHasher,
+	}
+}
+
+// SetHasher задаёт хэш-функцию. Вызывать до добавления элементов
+func (cms *Sketch) SetHasher(h Hasher) {
+	cms.hasher = h
+}
+
+// Инициализация таблицы и хэшей после создания структуры
+func (cms *Sketch) Init() {
+	//по строкам
+	for i := range cms.table {
+		//Все элементы инициализируются нулями
+		cms.table[i] = make([]int, cms.width) // создание строки таблицы
+		//вычисляем значение соли (seed)
+		//преобразуем в  64-битное целое число
+		cms.hashes[i] = uint64(i + 1)
+	}
+}
+
+// генерирует хэш от строки и соли, возвращает индекс в таблице
+func (cms *Sketch) HashIndex(s string, seed uint64) int {
+	// две независимые половины 128-битного хэша
+	v1, v2 := cms.hasher.Sum128([]byte(s))
+
+	// Генерируем разные индексы для каждого хеша, используя seed
+	mixed := v1 + seed*v2
+	return int(mixed % uint64(cms.width))
+}
+
+// Add увеличивает счётчик для элемента на заданное количество
+func (cms *Sketch) Add(s string, count int) {
+	// cms.depth - количество хеш-функций
+	for i := 0; i < cms.depth; i++ {
+		idx := cms.HashIndex(s, cms.hashes[i]) // вычисляем индекс
+		cms.table[i][idx] += count             // увеличиваем счётчик в ячейке
+	}
+}
+
+// Count возвращает оценочное минимальное количество вхождений элемента
+func (cms *Sketch) Count(s string) int {
+	min := math.MaxInt32 // 2 миллиарда
+	for i := 0; i < cms.depth; i++ {
+		idx := cms.HashIndex(s, cms.hashes[i]) // вычисляем индекс
+		if cms.table[i][idx] < min {           // если значение в ячейке меньше текущего минимума
+			min = cms.table[i][idx] // обновляем минимум
+		}
+	}
+	return min // возвращаем минимальное значение (оценка частоты)
+}
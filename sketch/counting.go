@@ -0,0 +1,122 @@
+package sketch
+
+import "errors"
+
+const (
+	counterBits = 4                  // ширина одного счётчика в битах
+	counterMax  = 1<<counterBits - 1 // значение насыщенного счётчика
+	counterMask = uint64(counterMax) // маска одного счётчика
+	perWord     = 64 / counterBits   // счётчиков в одном uint64
+)
+
+var (
+	// ErrCounterOverflow — один из счётчиков достиг максимума и больше
+	// не увеличивается. Элемент всё равно добавлен, но удалять элементы,
+	// попавшие в этот счётчик, уже нельзя точно
+	ErrCounterOverflow = errors.New("sketch: переполнение счётчика")
+	// ErrCounterUnderflow — удаляемого элемента точно нет в фильтре.
+	// Фильтр при этом не изменяется
+	ErrCounterUnderflow = errors.New("sketch: удаление отсутствующего элемента")
+)
+
+// CountingBloomFilter — фильтр Блума со счётчиками вместо битов,
+// поддерживающий удаление элементов
+type CountingBloomFilter struct {
+	counters []uint64 // 4-битные счётчики, по 16 в каждом uint64
+
+	size      int // количество счётчиков
+	hashCount int // количество хэш-функций (параметр k)
+
+	hasher Hasher
+}
+
+func NewCountingBloomFilter(size, hashCount int) *CountingBloomFilter {
+	return &CountingBloomFilter{
+		counters:  make([]uint64, (size+perWord-1)/perWord),
+		size:      size,
+		hashCount: hashCount,
+		hasher:    DefaultHasher,
+	}
+}
+
+// SetHasher задаёт хэш-функцию. Вызывать до добавления элементов
+func (cf *CountingBloomFilter) SetHasher(h Hasher) {
+	cf.hasher = h
+}
+
+// NewCountingBloomFilterWithRate подбирает размер и число хэш-функций
+// так же, как NewBloomFilterWithRate
+func NewCountingBloomFilterWithRate(n int, p float64) (*CountingBloomFilter, error) {
+	size, hashCount, err := OptimalParams(n, p)
+	if err != nil {
+		return nil, err
+	}
+	return NewCountingBloomFilter(size, hashCount), nil
+}
+
+// get возвращает значение счётчика idx
+func (cf *CountingBloomFilter) get(idx int) uint64 {
+	return cf.counters[idx/perWord] >> (idx % perWord * counterBits) & counterMask
+}
+
+// inc увеличивает счётчик idx на delta (1 или -1 в дополнительном коде)
+func (cf *CountingBloomFilter) inc(idx int, delta uint64) {
+	cf.counters[idx/perWord] += delta << (idx % perWord * counterBits)
+}
+
+// Add добавляет элемент. Возвращает ErrCounterOverflow, если какой-то
+// из счётчиков уже насыщен
+func (cf *CountingBloomFilter) Add(item []byte) error {
+	var err error
+	v1, v2 := cf.hasher.Sum128(item)
+	for i := 0; i < cf.hashCount; i++ {
+		idx := location(v1, v2, i, cf.size)
+		if cf.get(idx) == counterMax {
+			// насыщенный счётчик остаётся на максимуме
+			err = ErrCounterOverflow
+			continue
+		}
+		cf.inc(idx, 1)
+	}
+	return err
+}
+
+// Remove удаляет ранее добавленный элемент. Если хотя бы один из счётчиков
+// элемента равен нулю, возвращает ErrCounterUnderflow и ничего не меняет
+func (cf *CountingBloomFilter) Remove(item []byte) error {
+	v1, v2 := cf.hasher.Sum128(item)
+	for i := 0; i < cf.hashCount; i++ {
+		idx := location(v1, v2, i, cf.size)
+		switch cf.get(idx) {
+		case 0:
+			// откатываем уже уменьшенные счётчики
+			for j := 0; j < i; j++ {
+				prev := location(v1, v2, j, cf.size)
+				if cf.get(prev) != counterMax {
+					cf.inc(prev, 1)
+				}
+			}
+			return ErrCounterUnderflow
+		case counterMax:
+			// истинное значение насыщенного счётчика неизвестно, не трогаем его
+		default:
+			cf.inc(idx, ^uint64(0))
+		}
+	}
+	return nil
+}
+
+func (cf *CountingBloomFilter) Nalich(item []byte) bool {
+	v1, v2 := cf.hasher.Sum128(item)
+	for i := 0; i < cf.hashCount; i++ {
+		if cf.get(location(v1, v2, i, cf.size)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Memory возвращает размер массива счётчиков в байтах
+func (cf *CountingBloomFilter) Memory() int {
+	return len(cf.counters) * 8
+}
@@ -0,0 +1,92 @@
+package sketch
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestCountingBloomFilterRemove(t *testing.T) {
+	const n = 10_000
+	cf, err := NewCountingBloomFilterWithRate(n, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		if err := cf.Add([]byte(fmt.Sprintf("element-%d", i))); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	// удаляем каждый второй элемент: остальные должны остаться
+	for i := 0; i < n; i += 2 {
+		if err := cf.Remove([]byte(fmt.Sprintf("element-%d", i))); err != nil {
+			t.Fatalf("Remove element-%d: %v", i, err)
+		}
+	}
+	for i := 1; i < n; i += 2 {
+		if !cf.Nalich([]byte(fmt.Sprintf("element-%d", i))) {
+			t.Fatalf("ложноотрицательный ответ для element-%d после удаления соседей", i)
+		}
+	}
+	removed := 0
+	for i := 0; i < n; i += 2 {
+		if !cf.Nalich([]byte(fmt.Sprintf("element-%d", i))) {
+			removed++
+		}
+	}
+	if removed < n/2*9/10 {
+		t.Errorf("после удаления не находится только %d элементов из %d", removed, n/2)
+	}
+}
+
+func TestCountingBloomFilterRemoveRollback(t *testing.T) {
+	cf := NewCountingBloomFilter(256, 4)
+	for i := 0; i < 40; i++ {
+		cf.Add([]byte(fmt.Sprintf("element-%d", i)))
+	}
+	// ищем отсутствующий элемент, у которого первый счётчик ненулевой,
+	// а какой-то из следующих нулевой: Remove успеет уменьшить первый
+	// и должен откатить его
+	var item []byte
+	for i := 0; item == nil; i++ {
+		cand := []byte(fmt.Sprintf("missing-%d", i))
+		v1, v2 := cf.hasher.Sum128(cand)
+		if cf.get(location(v1, v2, 0, cf.size)) != 0 && !cf.Nalich(cand) {
+			item = cand
+		}
+	}
+	before := slices.Clone(cf.counters)
+	if err := cf.Remove(item); !errors.Is(err, ErrCounterUnderflow) {
+		t.Fatalf("Remove отсутствующего элемента: %v", err)
+	}
+	if !slices.Equal(cf.counters, before) {
+		t.Error("неудачный Remove изменил счётчики")
+	}
+}
+
+func TestCountingBloomFilterOverflow(t *testing.T) {
+	cf := NewCountingBloomFilter(1024, 3)
+	key := []byte("hot-key")
+	for i := 1; i <= counterMax; i++ {
+		if err := cf.Add(key); err != nil {
+			t.Fatalf("Add №%d: %v", i, err)
+		}
+	}
+	// 16-е добавление упирается в насыщенные счётчики
+	for i := 0; i < 3; i++ {
+		if err := cf.Add(key); !errors.Is(err, ErrCounterOverflow) {
+			t.Fatalf("Add после %d добавлений: %v, want ErrCounterOverflow", counterMax+i, err)
+		}
+	}
+	// насыщенные счётчики не уменьшаются: элемент не пропадёт,
+	// сколько бы раз его ни удаляли
+	for i := 0; i < 2*counterMax; i++ {
+		if err := cf.Remove(key); err != nil {
+			t.Fatalf("Remove: %v", err)
+		}
+	}
+	if !cf.Nalich(key) {
+		t.Error("элемент с насыщенными счётчиками пропал после удалений")
+	}
+}
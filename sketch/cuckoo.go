@@ -0,0 +1,174 @@
+package sketch
+
+import (
+	"errors"
+	"math/bits"
+)
+
+const (
+	cuckooBucketSize = 4    // отпечатков в одной корзине
+	cuckooMaxKicks   = 500  // сколько раз можно вытеснять отпечатки при вставке
+	cuckooMaxLoad    = 0.95 // заполнение, на которое рассчитывается размер
+)
+
+// ErrCuckooFull — для нового отпечатка не нашлось места. Фильтр при этом
+// остаётся в том же состоянии, что и до вызова Add
+var ErrCuckooFull = errors.New("sketch: кукушкин фильтр заполнен")
+
+// CuckooFilter — кукушкин фильтр (Fan и др., 2014). Хранит 16-битные
+// отпечатки элементов в корзинах по 4. Каждый элемент может лежать в одной
+// из двух корзин, вторая вычисляется по первой и отпечатку
+// (partial-key cuckoo hashing): i2 = i1 xor hash(fp). В отличие
+// от BloomFilter поддерживает удаление
+type CuckooFilter struct {
+	buckets [][cuckooBucketSize]uint16 // 0 — пустая ячейка
+	mask    uint64                     // len(buckets) - 1, число корзин — степень двойки
+	count   int                        // сколько отпечатков хранится
+
+	hasher Hasher
+	rnd    uint64 // состояние генератора для выбора вытесняемой ячейки
+}
+
+// NewCuckooFilter создаёт фильтр примерно на capacity элементов
+func NewCuckooFilter(capacity int) (*CuckooFilter, error) {
+	if capacity <= 0 || capacity > 1<<40 {
+		return nil, ErrInvalidParams
+	}
+	n := uint64(float64(capacity)/(cuckooBucketSize*cuckooMaxLoad)) + 1
+	// округляем число корзин вверх до степени двойки
+	n = 1 << bits.Len64(n-1)
+	return &CuckooFilter{
+		buckets: make([][cuckooBucketSize]uint16, n),
+		mask:    n - 1,
+		hasher:  DefaultHasher,
+		rnd:     0x9e3779b97f4a7c15,
+	}, nil
+}
+
+// SetHasher задаёт хэш-функцию. Вызывать до добавления элементов
+func (cf *CuckooFilter) SetHasher(h Hasher) {
+	cf.hasher = h
+}
+
+// indexAndFingerprint возвращает первую корзину и отпечаток элемента
+func (cf *CuckooFilter) indexAndFingerprint(item []byte) (uint64, uint16) {
+	v1, v2 := cf.hasher.Sum128(item)
+	fp := uint16(v2 >> 48)
+	if fp == 0 {
+		fp = 1 // ноль означает пустую ячейку
+	}
+	return v1 & cf.mask, fp
+}
+
+// altIndex возвращает вторую корзину. Операция симметрична:
+// altIndex(altIndex(i, fp), fp) == i
+func (cf *CuckooFilter) altIndex(i uint64, fp uint16) uint64 {
+	return (i ^ uint64(fp)*0x5bd1e995) & cf.mask
+}
+
+// insert кладёт отпечаток в свободную ячейку корзины i
+func (cf *CuckooFilter) insert(i uint64, fp uint16) bool {
+	b := &cf.buckets[i]
+	for s := range b {
+		if b[s] == 0 {
+			b[s] = fp
+			return true
+		}
+	}
+	return false
+}
+
+// kick — одно вытеснение, нужное для отката неудачной вставки
+type kick struct {
+	bucket uint64
+	slot   int
+	old    uint16
+}
+
+// Add добавляет элемент. Если после cuckooMaxKicks вытеснений место
+// не нашлось, все перемещения откатываются и возвращается ErrCuckooFull
+func (cf *CuckooFilter) Add(item []byte) error {
+	i1, fp := cf.indexAndFingerprint(item)
+	i2 := cf.altIndex(i1, fp)
+	if cf.insert(i1, fp) || cf.insert(i2, fp) || cf.relocate(i1, i2, fp) {
+		cf.count++
+		return nil
+	}
+	return ErrCuckooFull
+}
+
+// relocate освобождает место, вытесняя случайные отпечатки в их другие
+// корзины. При неудаче откатывает все перемещения
+func (cf *CuckooFilter) relocate(i1, i2 uint64, fp uint16) bool {
+	var path [cuckooMaxKicks]kick
+	i := i1
+	if cf.random()&1 == 1 {
+		i = i2
+	}
+	for n := 0; n < cuckooMaxKicks; n++ {
+		s := int(cf.random() % cuckooBucketSize)
+		path[n] = kick{bucket: i, slot: s, old: cf.buckets[i][s]}
+		fp, cf.buckets[i][s] = cf.buckets[i][s], fp
+		i = cf.altIndex(i, fp)
+		if cf.insert(i, fp) {
+			return true
+		}
+	}
+
+	for n := cuckooMaxKicks - 1; n >= 0; n-- {
+		cf.buckets[path[n].bucket][path[n].slot] = path[n].old
+	}
+	return false
+}
+
+// Lookup проверяет, есть ли элемент в фильтре. Как и у фильтра Блума,
+// возможны ложные срабатывания, но не пропуски
+func (cf *CuckooFilter) Lookup(item []byte) bool {
+	i1, fp := cf.indexAndFingerprint(item)
+	i2 := cf.altIndex(i1, fp)
+	for s := 0; s < cuckooBucketSize; s++ {
+		if cf.buckets[i1][s] == fp || cf.buckets[i2][s] == fp {
+			return true
+		}
+	}
+	return false
+}
+
+// Delete удаляет одну копию элемента. Удалять можно только то, что было
+// добавлено, иначе можно стереть отпечаток другого элемента
+func (cf *CuckooFilter) Delete(item []byte) bool {
+	i1, fp := cf.indexAndFingerprint(item)
+	for _, i := range [2]uint64{i1, cf.altIndex(i1, fp)} {
+		for s := range cf.buckets[i] {
+			if cf.buckets[i][s] == fp {
+				cf.buckets[i][s] = 0
+				cf.count--
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Count возвращает количество хранимых отпечатков
+func (cf *CuckooFilter) Count() int {
+	return cf.count
+}
+
+// LoadFactor возвращает долю занятых ячеек
+func (cf *CuckooFilter) LoadFactor() float64 {
+	return float64(cf.count) / float64(len(cf.buckets)*cuckooBucketSize)
+}
+
+// Memory возвращает размер таблицы в байтах
+func (cf *CuckooFilter) Memory() int {
+	return len(cf.buckets) * cuckooBucketSize * 2
+}
+
+// random — xorshift64, чтобы выбор вытесняемой ячейки был воспроизводимым
+func (cf *CuckooFilter) random() uint64 {
+	cf.rnd ^= cf.rnd << 13
+	cf.rnd ^= cf.rnd >> 7
+	cf.rnd ^= cf.rnd << 17
+	return cf.rnd
+}
@@ -0,0 +1,99 @@
+package sketch
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestNewCuckooFilterParams(t *testing.T) {
+	for _, capacity := range []int{0, -1, -1000} {
+		if _, err := NewCuckooFilter(capacity); err != ErrInvalidParams {
+			t.Errorf("NewCuckooFilter(%d): %v, want ErrInvalidParams", capacity, err)
+		}
+	}
+}
+
+func TestCuckooFilterAddLookupDelete(t *testing.T) {
+	const n = 10_000
+	cf, err := NewCuckooFilter(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		if err := cf.Add([]byte(fmt.Sprintf("element-%d", i))); err != nil {
+			t.Fatalf("Add element-%d: %v", i, err)
+		}
+	}
+	if cf.Count() != n {
+		t.Errorf("Count = %d, want %d", cf.Count(), n)
+	}
+	if lf := cf.LoadFactor(); lf != float64(n)/float64(len(cf.buckets)*cuckooBucketSize) {
+		t.Errorf("LoadFactor = %v", lf)
+	}
+	for i := 0; i < n; i++ {
+		if !cf.Lookup([]byte(fmt.Sprintf("element-%d", i))) {
+			t.Fatalf("ложноотрицательный ответ для element-%d", i)
+		}
+	}
+
+	for i := 0; i < n; i += 2 {
+		if !cf.Delete([]byte(fmt.Sprintf("element-%d", i))) {
+			t.Fatalf("Delete element-%d не нашёл отпечаток", i)
+		}
+	}
+	if cf.Count() != n/2 {
+		t.Errorf("Count после удаления = %d, want %d", cf.Count(), n/2)
+	}
+	stale := 0
+	for i := 0; i < n; i++ {
+		found := cf.Lookup([]byte(fmt.Sprintf("element-%d", i)))
+		if i%2 == 1 && !found {
+			t.Fatalf("удаление соседей стёрло element-%d", i)
+		}
+		if i%2 == 0 && found {
+			stale++
+		}
+	}
+	// удалённый элемент находится, только если совпал отпечаток с соседом
+	if stale > n/100 {
+		t.Errorf("после удаления находятся %d элементов из %d", stale, n/2)
+	}
+	// Count уменьшается, только если Delete нашёл отпечаток
+	count := cf.Count()
+	if cf.Delete([]byte("missing")) != (cf.Count() == count-1) {
+		t.Errorf("Count после Delete = %d, было %d", cf.Count(), count)
+	}
+}
+
+func TestCuckooFilterFull(t *testing.T) {
+	cf, _ := NewCuckooFilter(100)
+	var added [][]byte
+	var err error
+	for i := 0; err == nil; i++ {
+		item := []byte(fmt.Sprintf("element-%d", i))
+		before := slices.Clone(cf.buckets)
+		if err = cf.Add(item); err == nil {
+			added = append(added, item)
+			continue
+		}
+		if err != ErrCuckooFull {
+			t.Fatalf("Add: %v, want ErrCuckooFull", err)
+		}
+		// откат вытеснений возвращает таблицу в исходное состояние
+		if !slices.Equal(cf.buckets, before) {
+			t.Error("неудачный Add изменил таблицу")
+		}
+	}
+	if cf.Count() != len(added) {
+		t.Errorf("Count = %d, want %d", cf.Count(), len(added))
+	}
+	if lf := cf.LoadFactor(); lf < 0.8 || lf > 1 {
+		t.Errorf("фильтр заполнился при LoadFactor = %.3f", lf)
+	}
+	for _, item := range added {
+		if !cf.Lookup(item) {
+			t.Fatalf("после ErrCuckooFull пропал %s", item)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+// Package sketch содержит вероятностные структуры данных для работы
+// с большими потоками: фильтр Блума (BloomFilter), Count-Min Sketch (Sketch),
+// HyperLogLog и резервуарную выборку (Reservoir).
+//
+// Программы для замеров лежат в cmd/blum, cmd/count, cmd/hyper, cmd/reserv,
+// пошаговые демонстрации — в cmd/*-demo.
+package sketch
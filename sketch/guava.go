@@ -0,0 +1,149 @@
+package sketch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Совместимость с com.google.common.hash.BloomFilter из Guava.
+//
+// BloomFilter.writeTo пишет (DataOutputStream, big-endian):
+//
+//	[0]     номер стратегии хэширования (BloomFilterStrategies.ordinal())
+//	[1]     число хэш-функций, беззнаковый байт
+//	[2:6]   число слов long в битовом массиве
+//	[6:..]  слова long
+//
+// Биты внутри слова нумеруются так же, как в BloomFilter: бит idx лежит
+// в слове idx/64 под маской 1 << (idx%64). Размер фильтра в Guava всегда
+// кратен 64 битам.
+//
+// Элементы хэшируются Murmur3 x64 128 с seed 0 от байт, которые funnel
+// подаёт в хэшер. Чтобы Go и Java давали одни и те же биты, в Java нужно
+// использовать Funnels.byteArrayFunnel() или Funnels.stringFunnel(UTF_8):
+// строка хэшируется как её байты в UTF-8. Funnels.unencodedCharsFunnel()
+// хэширует UTF-16LE, Funnels.longFunnel() — 8 байт little-endian
+const (
+	guavaMitz32 = 0 // MURMUR128_MITZ_32
+	guavaMitz64 = 1 // MURMUR128_MITZ_64, стратегия по умолчанию
+)
+
+// ErrNotGuava возвращается из WriteGuavaTo для фильтра, который нельзя
+// прочитать в Guava: не режим Guava, другой Hasher или больше 255 хэш-функций
+var ErrNotGuava = errors.New("sketch: фильтр несовместим с форматом Guava")
+
+// NewGuavaBloomFilter создаёт фильтр так же, как BloomFilter.create в Guava
+// с теми же expectedInsertions и fpp: те же размер, число хэш-функций
+// и стратегия MURMUR128_MITZ_64. Размеры считаются по формулам Guava
+// (с отбрасыванием дробной части), поэтому могут на несколько бит
+// отличаться от OptimalParams
+func NewGuavaBloomFilter(expectedInsertions int, fpp float64) (*BloomFilter, error) {
+	if expectedInsertions <= 0 || !(fpp > 0 && fpp < 1) {
+		return nil, ErrInvalidParams
+	}
+	n := float64(expectedInsertions)
+	numBits := int64(-n * math.Log(fpp) / (math.Ln2 * math.Ln2))
+	// Math.round в Java — floor(x + 0.5)
+	hashCount := max(1, int(math.Floor(float64(numBits)/n*math.Ln2+0.5)))
+	if numBits <= 0 || hashCount > math.MaxUint8 {
+		return nil, ErrInvalidParams
+	}
+	return newGuavaBloomFilter(int((numBits+63)/64), hashCount, indexGuava64), nil
+}
+
+func newGuavaBloomFilter(words, hashCount int, mode indexMode) *BloomFilter {
+	bf := NewBloomFilter(words*64, hashCount)
+	bf.hasher = Murmur3
+	bf.mode = mode
+	return bf
+}
+
+// WriteGuavaTo пишет фильтр в формате BloomFilter.writeTo из Guava.
+// Фильтр должен быть создан NewGuavaBloomFilter или прочитан ReadGuavaFrom
+func (bf *BloomFilter) WriteGuavaTo(w io.Writer) (int64, error) {
+	var strategy byte
+	switch bf.mode {
+	case indexGuava32:
+		strategy = guavaMitz32
+	case indexGuava64:
+		strategy = guavaMitz64
+	default:
+		return 0, fmt.Errorf("%w: фильтр не в режиме Guava", ErrNotGuava)
+	}
+	if !sameHasher(bf.hasher, Murmur3) {
+		return 0, fmt.Errorf("%w: Guava хэширует только Murmur3", ErrNotGuava)
+	}
+	if bf.hashCount > math.MaxUint8 || bf.size != len(bf.bitSet)*64 {
+		return 0, ErrNotGuava
+	}
+
+	var header [6]byte
+	header[0] = strategy
+	header[1] = byte(bf.hashCount)
+	binary.BigEndian.PutUint32(header[2:6], uint32(len(bf.bitSet)))
+	n, err := w.Write(header[:])
+	total := int64(n)
+	if err != nil {
+		return total, err
+	}
+
+	var chunk [512 * 8]byte
+	for i := 0; i < len(bf.bitSet); {
+		j := 0
+		for ; j < len(chunk) && i < len(bf.bitSet); j, i = j+8, i+1 {
+			binary.BigEndian.PutUint64(chunk[j:], bf.bitSet[i])
+		}
+		n, err = w.Write(chunk[:j])
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// ReadGuavaFrom читает фильтр, записанный BloomFilter.writeTo из Guava.
+// Поддерживаются обе стратегии Guava; Hasher становится Murmur3.
+// При ошибке фильтр не изменяется
+func (bf *BloomFilter) ReadGuavaFrom(r io.Reader) (int64, error) {
+	var header [6]byte
+	n, err := io.ReadFull(r, header[:])
+	total := int64(n)
+	if err != nil {
+		return total, truncated(err)
+	}
+	var mode indexMode
+	switch header[0] {
+	case guavaMitz32:
+		mode = indexGuava32
+	case guavaMitz64:
+		mode = indexGuava64
+	default:
+		return total, fmt.Errorf("%w: стратегия Guava %d", ErrUnsupportedFormat, header[0])
+	}
+	hashCount := int(header[1])
+	words := int32(binary.BigEndian.Uint32(header[2:6]))
+	if hashCount == 0 || words <= 0 {
+		return total, ErrCorrupt
+	}
+
+	// как и в ReadFrom, буфер растёт по мере чтения
+	var body bytes.Buffer
+	m, err := io.CopyN(&body, r, int64(words)*8)
+	total += m
+	if err != nil {
+		return total, truncated(err)
+	}
+
+	raw := body.Bytes()
+	g := newGuavaBloomFilter(int(words), hashCount, mode)
+	for i := range g.bitSet {
+		g.bitSet[i] = binary.BigEndian.Uint64(raw[i*8:])
+	}
+	*bf = *g
+	return total, nil
+}
@@ -0,0 +1,108 @@
+package sketch
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// элементы с известными хэшами Murmur3 (см. TestMurmur3Vectors)
+var guavaItems = []string{"The quick brown fox jumps over the lazy dog", "hell"}
+
+func TestGuavaGolden(t *testing.T) {
+	// фильтры на 128 бит с тремя хэш-функциями после put() обоих элементов;
+	// биты посчитаны по BloomFilterStrategies из исходников Guava
+	tests := []struct {
+		name   string
+		golden string
+	}{
+		{"MITZ_64", "010300000002" + "0008400000000000" + "0420108000000000"},
+		{"MITZ_32", "000300000002" + "0200800421000000" + "0000000020000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			golden, _ := hex.DecodeString(tt.golden)
+			empty := append(golden[:6:6], make([]byte, 16)...)
+
+			var bf BloomFilter
+			if _, err := bf.ReadGuavaFrom(bytes.NewReader(empty)); err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range guavaItems {
+				bf.Add([]byte(s))
+			}
+			var buf bytes.Buffer
+			n, err := bf.WriteGuavaTo(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != int64(len(golden)) || !bytes.Equal(buf.Bytes(), golden) {
+				t.Fatalf("WriteGuavaTo = %x, want %x", buf.Bytes(), golden)
+			}
+
+			var got BloomFilter
+			if _, err := got.ReadGuavaFrom(bytes.NewReader(golden)); err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range guavaItems {
+				if !got.Nalich([]byte(s)) {
+					t.Errorf("Nalich(%q) = false после чтения", s)
+				}
+			}
+			if got.Nalich([]byte("hello")) {
+				t.Error("Nalich(\"hello\") = true в почти пустом фильтре")
+			}
+		})
+	}
+}
+
+func TestNewGuavaBloomFilterSizing(t *testing.T) {
+	// BloomFilter.create(funnel, 1000, 0.01) в Guava: 9585 бит -> 150 long, 7 хэш-функций
+	bf, err := NewGuavaBloomFilter(1000, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bf.Size() != 9600 || bf.HashCount() != 7 {
+		t.Errorf("size, k = %d, %d, want 9600, 7", bf.Size(), bf.HashCount())
+	}
+}
+
+func TestGuavaNativeRoundTrip(t *testing.T) {
+	bf, err := NewGuavaBloomFilter(1000, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range guavaItems {
+		bf.Add([]byte(s))
+	}
+	data, err := bf.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got BloomFilter
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if got.mode != indexGuava64 {
+		t.Fatalf("mode = %d после UnmarshalBinary", got.mode)
+	}
+	var a, b bytes.Buffer
+	bf.WriteGuavaTo(&a)
+	if _, err := got.WriteGuavaTo(&b); err != nil || !bytes.Equal(a.Bytes(), b.Bytes()) {
+		t.Errorf("WriteGuavaTo после UnmarshalBinary отличается: %v", err)
+	}
+}
+
+func TestGuavaErrors(t *testing.T) {
+	if _, err := NewBloomFilter(128, 3).WriteGuavaTo(new(bytes.Buffer)); !errors.Is(err, ErrNotGuava) {
+		t.Errorf("WriteGuavaTo обычного фильтра: %v, want ErrNotGuava", err)
+	}
+	var bf BloomFilter
+	if _, err := bf.ReadGuavaFrom(bytes.NewReader([]byte{2, 3, 0, 0, 0, 1})); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("неизвестная стратегия: %v", err)
+	}
+	if _, err := bf.ReadGuavaFrom(bytes.NewReader([]byte{1, 3, 0, 0, 0, 2, 0})); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("обрезанный массив: %v", err)
+	}
+}
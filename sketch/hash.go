@@ -0,0 +1,246 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// Hasher вычисляет 128-битный хэш элемента в виде двух 64-битных половин.
+// Половины должны быть независимы: из них по схеме двойного хэширования
+// h1 + i*h2 получаются k хэш-функций фильтров и скетчей
+type Hasher interface {
+	Sum128(data []byte) (uint64, uint64)
+}
+
+var (
+	// FNV — FNV-1a 128 с финальным перемешиванием половин
+	FNV Hasher = fnvHasher{}
+	// XXHash — xxHash64 с двумя разными seed
+	XXHash Hasher = xxHasher{}
+	// Murmur3 — MurmurHash3 x64_128
+	Murmur3 Hasher = murmur3Hasher{}
+
+	// DefaultHasher используется всеми структурами пакета, если не задан другой
+	DefaultHasher = Murmur3
+)
+
+// Идентификаторы схем хэширования в сохранённых файлах
+const (
+	// hashSchemeLegacyFNV — старая схема с двумя одинаковыми FNV-1a 64
+	// (h1 == h2). Больше не поддерживается
+	hashSchemeLegacyFNV = 1
+	hashSchemeFNV       = 2
+	hashSchemeXXHash    = 3
+	hashSchemeMurmur3   = 4
+)
+
+// hashSchemeOf возвращает идентификатор встроенного хэшера
+func hashSchemeOf(h Hasher) (byte, bool) {
+	switch h {
+	case FNV:
+		return hashSchemeFNV, true
+	case XXHash:
+		return hashSchemeXXHash, true
+	case Murmur3:
+		return hashSchemeMurmur3, true
+	}
+	return 0, false
+}
+
+// hasherOf возвращает встроенный хэшер по идентификатору схемы
+func hasherOf(scheme byte) (Hasher, bool) {
+	switch scheme {
+	case hashSchemeFNV:
+		return FNV, true
+	case hashSchemeXXHash:
+		return XXHash, true
+	case hashSchemeMurmur3:
+		return Murmur3, true
+	}
+	return nil, false
+}
+
+type fnvHasher struct{}
+
+const (
+	fnv128OffsetHigh = 0x6c62272e07bb0142
+	fnv128OffsetLow  = 0x62b821756295c58d
+	fnv128PrimeLow   = 0x13b // простое число FNV-128 = 2^88 + 0x13b
+	fnv128PrimeShift = 24
+)
+
+// Sum128 перемешивает половины FNV-1a 128: без этого у коротких похожих
+// ключей старшая половина почти не зависит от последних байтов
+func (fnvHasher) Sum128(data []byte) (uint64, uint64) {
+	hi, lo := fnv128a(data)
+	return murmurFmix(hi), murmurFmix(lo)
+}
+
+// fnv128a считает FNV-1a 128 без выделения памяти (как hash/fnv.New128a)
+func fnv128a(data []byte) (uint64, uint64) {
+	hi, lo := uint64(fnv128OffsetHigh), uint64(fnv128OffsetLow)
+	for _, c := range data {
+		lo ^= uint64(c)
+		// (hi, lo) * (2^88 + 0x13b) по модулю 2^128
+		s0, s1 := bits.Mul64(fnv128PrimeLow, lo)
+		s0 += lo<<fnv128PrimeShift + fnv128PrimeLow*hi
+		hi, lo = s0, s1
+	}
+	return hi, lo
+}
+
+type xxHasher struct{}
+
+// xxSecondSeed — seed второй половины хэша
+const xxSecondSeed = 0x9e3779b97f4a7c15
+
+func (xxHasher) Sum128(data []byte) (uint64, uint64) {
+	return xxhash64(data, 0), xxhash64(data, xxSecondSeed)
+}
+
+type murmur3Hasher struct{}
+
+func (murmur3Hasher) Sum128(data []byte) (uint64, uint64) {
+	return murmur3Sum128(data, 0)
+}
+
+const (
+	xxPrime1 uint64 = 11400714785074694791
+	xxPrime2 uint64 = 14029467366897019727
+	xxPrime3 uint64 = 1609587929392839161
+	xxPrime4 uint64 = 9650029242287828579
+	xxPrime5 uint64 = 2870177450012600261
+)
+
+// xxhash64 — XXH64 (https://github.com/Cyan4973/xxHash)
+func xxhash64(b []byte, seed uint64) uint64 {
+	n := len(b)
+	var h uint64
+
+	if n >= 32 {
+		v1 := seed + xxPrime1 + xxPrime2
+		v2 := seed + xxPrime2
+		v3 := seed
+		v4 := seed - xxPrime1
+		for ; len(b) >= 32; b = b[32:] {
+			v1 = xxRound(v1, binary.LittleEndian.Uint64(b[0:8]))
+			v2 = xxRound(v2, binary.LittleEndian.Uint64(b[8:16]))
+			v3 = xxRound(v3, binary.LittleEndian.Uint64(b[16:24]))
+			v4 = xxRound(v4, binary.LittleEndian.Uint64(b[24:32]))
+		}
+		h = bits.RotateLeft64(v1, 1) + bits.RotateLeft64(v2, 7) +
+			bits.RotateLeft64(v3, 12) + bits.RotateLeft64(v4, 18)
+		h = xxMergeRound(h, v1)
+		h = xxMergeRound(h, v2)
+		h = xxMergeRound(h, v3)
+		h = xxMergeRound(h, v4)
+	} else {
+		h = seed + xxPrime5
+	}
+
+	h += uint64(n)
+
+	for ; len(b) >= 8; b = b[8:] {
+		h ^= xxRound(0, binary.LittleEndian.Uint64(b[:8]))
+		h = bits.RotateLeft64(h, 27)*xxPrime1 + xxPrime4
+	}
+	if len(b) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(b[:4])) * xxPrime1
+		h = bits.RotateLeft64(h, 23)*xxPrime2 + xxPrime3
+		b = b[4:]
+	}
+	for _, c := range b {
+		h ^= uint64(c) * xxPrime5
+		h = bits.RotateLeft64(h, 11) * xxPrime1
+	}
+
+	h ^= h >> 33
+	h *= xxPrime2
+	h ^= h >> 29
+	h *= xxPrime3
+	h ^= h >> 32
+	return h
+}
+
+func xxRound(acc, input uint64) uint64 {
+	acc += input * xxPrime2
+	acc = bits.RotateLeft64(acc, 31)
+	return acc * xxPrime1
+}
+
+func xxMergeRound(acc, val uint64) uint64 {
+	acc ^= xxRound(0, val)
+	return acc*xxPrime1 + xxPrime4
+}
+
+const (
+	murmurC1 uint64 = 0x87c37b91114253d5
+	murmurC2 uint64 = 0x4cf5ad432745937f
+)
+
+// murmur3Sum128 — MurmurHash3_x64_128 (https://github.com/aappleby/smhasher)
+func murmur3Sum128(b []byte, seed uint64) (uint64, uint64) {
+	n := len(b)
+	h1, h2 := seed, seed
+
+	for ; len(b) >= 16; b = b[16:] {
+		k1 := binary.LittleEndian.Uint64(b[0:8])
+		k2 := binary.LittleEndian.Uint64(b[8:16])
+
+		h1 ^= murmurMixK1(k1)
+		h1 = bits.RotateLeft64(h1, 27)
+		h1 += h2
+		h1 = h1*5 + 0x52dce729
+
+		h2 ^= murmurMixK2(k2)
+		h2 = bits.RotateLeft64(h2, 31)
+		h2 += h1
+		h2 = h2*5 + 0x38495ab5
+	}
+
+	// хвост короче 16 байт
+	var k1, k2 uint64
+	for i := len(b) - 1; i >= 8; i-- {
+		k2 = k2<<8 | uint64(b[i])
+	}
+	if len(b) > 8 {
+		h2 ^= murmurMixK2(k2)
+	}
+	for i := min(len(b), 8) - 1; i >= 0; i-- {
+		k1 = k1<<8 | uint64(b[i])
+	}
+	if len(b) > 0 {
+		h1 ^= murmurMixK1(k1)
+	}
+
+	h1 ^= uint64(n)
+	h2 ^= uint64(n)
+	h1 += h2
+	h2 += h1
+	h1 = murmurFmix(h1)
+	h2 = murmurFmix(h2)
+	h1 += h2
+	h2 += h1
+	return h1, h2
+}
+
+func murmurMixK1(k uint64) uint64 {
+	k *= murmurC1
+	k = bits.RotateLeft64(k, 31)
+	return k * murmurC2
+}
+
+func murmurMixK2(k uint64) uint64 {
+	k *= murmurC2
+	k = bits.RotateLeft64(k, 33)
+	return k * murmurC1
+}
+
+func murmurFmix(k uint64) uint64 {
+	k ^= k >> 33
+	k *= 0xff51afd7ed558ccd
+	k ^= k >> 33
+	k *= 0xc4ceb9fe1a85ec53
+	k ^= k >> 33
+	return k
+}
@@ -0,0 +1,75 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"testing"
+)
+
+func TestXXHash64Vectors(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint64
+	}{
+		{"", 0xef46db3751d8e999},
+		{"a", 0xd24ec4f1a98c6e5b},
+		{"abc", 0x44bc2cf5ad770999},
+		{"Nobody inspects the spammish repetition", 0xfbcea83c8a378bf1},
+	}
+	for _, tt := range tests {
+		if got := xxhash64([]byte(tt.in), 0); got != tt.want {
+			t.Errorf("xxhash64(%q) = %#x, want %#x", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMurmur3Vectors(t *testing.T) {
+	// значения из тестов Guava (Murmur3Hash128Test)
+	tests := []struct {
+		seed   uint64
+		h1, h2 uint64
+		in     string
+	}{
+		{0, 0x629942693e10f867, 0x92db0b82baeb5347, "hell"},
+		{1, 0xa78ddff5adae8d10, 0x128900ef20900135, "hello"},
+		{2, 0x8a486b23f422e826, 0xf962a2c58947765f, "hello "},
+		{3, 0x2ea59f466f6bed8c, 0xc610990acc428a17, "hello w"},
+		{4, 0x79f6305a386c572c, 0x46305aed3483b94e, "hello wo"},
+		{5, 0xc2219d213ec1f1b5, 0xa1d8e2e0a52785bd, "hello wor"},
+		{0, 0xe34bbc7bbc071b6c, 0x7a433ca9c49a9347, "The quick brown fox jumps over the lazy dog"},
+		{0, 0x658ca970ff85269a, 0x43fee3eaa68e5c3e, "The quick brown fox jumps over the lazy cog"},
+	}
+	for _, tt := range tests {
+		h1, h2 := murmur3Sum128([]byte(tt.in), tt.seed)
+		if h1 != tt.h1 || h2 != tt.h2 {
+			t.Errorf("murmur3Sum128(%q, %d) = %#x, %#x, want %#x, %#x", tt.in, tt.seed, h1, h2, tt.h1, tt.h2)
+		}
+	}
+}
+
+func TestFNVMatchesStdlib(t *testing.T) {
+	for _, in := range []string{"", "a", "element-1", "The quick brown fox jumps over the lazy dog"} {
+		h := fnv.New128a()
+		h.Write([]byte(in))
+		sum := h.Sum(nil)
+		hi, lo := fnv128a([]byte(in))
+		if hi != binary.BigEndian.Uint64(sum[:8]) || lo != binary.BigEndian.Uint64(sum[8:]) {
+			t.Errorf("fnv128a(%q) = %#x%016x, want %x", in, hi, lo, sum)
+		}
+	}
+}
+
+func TestHasherHalvesDiffer(t *testing.T) {
+	for _, h := range []Hasher{FNV, XXHash, Murmur3} {
+		same := 0
+		for i := 0; i < 1000; i++ {
+			v1, v2 := h.Sum128([]byte{byte(i), byte(i >> 8)})
+			if v1 == v2 {
+				same++
+			}
+		}
+		if same != 0 {
+			t.Errorf("%T: %d хэшей с v1 == v2", h, same)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package sketch
+
+import (
+	"math"
+	"math/bits"
+	"slices"
+)
+
+//go:generate go run gen_hllbias.go
+
+// Допустимая точность HyperLogLog: m = 2^p регистров
+const (
+	MinPrecision = 4
+	MaxPrecision = 18
+)
+
+type HyperLogLog struct {
+	registers []byte // m = 2^p ячеек памяти, m определяет точность алгоритма; nil, пока счётчик разреженный
+	p         uint8
+	hasher    Hasher
+
+	// разреженное представление (см. hyper_sparse.go)
+	sparse []byte   // отсортированные элементы, разности в varint
+	tmp    []uint32 // ещё не слитые в sparse элементы
+
+	mode    hllMode
+	coupons []uint32 // купоны DataSketches, пока регистров нет (см. hyper_datasketches.go)
+}
+
+// hllMode задаёт, как из хэша получаются регистр и rho. Режимы Redis
+// и DataSketches нужны только для совместимости (см. hyper_redis.go
+// и hyper_datasketches.go)
+type hllMode uint8
+
+const (
+	hllStandard     hllMode = iota // HyperLogLog++: старшие p бит — регистр
+	hllRedis                       // младшие 14 бит — регистр, MurmurHash64A
+	hllDataSketches                // купоны Apache DataSketches
+)
+
+// NewHyperLogLog создаёт счётчик с 2^p регистрами, p от 4 до 18.
+// Каждый регистр занимает байт, стандартная ошибка 1.04/sqrt(2^p):
+// при p = 12 (4 КБ) она около 1.6%, при p = 14 (16 КБ) — около 0.8%.
+// Пока элементов мало, счётчик хранится в разреженном виде и занимает
+// несколько байт на элемент
+func NewHyperLogLog(p int) (*HyperLogLog, error) {
+	if p < MinPrecision || p > MaxPrecision {
+		return nil, ErrInvalidParams
+	}
+	return &HyperLogLog{
+		p:      uint8(p),
+		hasher: DefaultHasher,
+	}, nil
+}
+
+// SetHasher задаёт хэш-функцию. Вызывать до добавления элементов
+func (hll *HyperLogLog) SetHasher(h Hasher) {
+	hll.hasher = h
+}
+
+// Add добавляет строку: хэширует её и передаёт младшую половину хэша
+// в AddHash. В режиме DataSketches нужны обе половины хэша, а пустые
+// строки, как и в DataSketches, пропускаются
+func (hll *HyperLogLog) Add(s string) {
+	v1, v2 := hll.hasher.Sum128(stringBytes(s))
+	if hll.mode == hllDataSketches {
+		if s != "" {
+			hll.addCoupon(dataSketchesCoupon(v1, v2))
+		}
+		return
+	}
+	hll.AddHash(v1)
+}
+
+// AddHash добавляет элемент по его 64-битному хэшу. Старшие p бит
+// выбирают регистр, по остальным 64-p битам считается rho — номер первой
+// единицы. За счёт всей ширины хэша оценка не упирается в 2^32 и годится
+// для кардинальностей порядка 10^18. В режимах Redis и DataSketches
+// разбор хэша другой, см. redisPattern и dataSketchesHashCoupon
+func (hll *HyperLogLog) AddHash(h uint64) {
+	switch hll.mode {
+	case hllRedis:
+		idx, rho := redisPattern(h)
+		hll.registers[idx] = max(hll.registers[idx], rho)
+		return
+	case hllDataSketches:
+		hll.addCoupon(dataSketchesHashCoupon(h))
+		return
+	}
+
+	// определяем индекс регистра
+	idx := h >> (64 - hll.p)
+
+	// вычисляем значение для обновленного регистра: считаем, сколько
+	// ведущих нулей в оставшихся битах, и прибавляем 1. Единица-ограничитель
+	// не даёт rho превысить 64-p+1, если все оставшиеся биты нулевые
+	rho := byte(bits.LeadingZeros64(h<<hll.p|1<<(hll.p-1))) + 1
+
+	if hll.registers == nil {
+		hll.addSparse(h, rho)
+		return
+	}
+
+	//Если новое значение больше, чем то, что уже хранится в регистре
+	if rho > hll.registers[idx] {
+		hll.registers[idx] = rho
+	}
+}
+
+// hllThresholds[p-4] — до какой оценки линейного счёта ему доверять
+// больше, чем исправленной оценке HyperLogLog (Heule и др., 2013)
+var hllThresholds = [...]float64{
+	10, 20, 40, 80, 220, 400, 900, 1800, 3100, 6500, 11500, 20000, 50000, 120000, 350000,
+}
+
+// делает оценку количества уникальных элементов по алгоритму HyperLogLog++
+func (hll *HyperLogLog) Estimate() float64 {
+	if hll.registers == nil {
+		if hll.mode == hllDataSketches {
+			return hll.estimateCoupons()
+		}
+		return hll.estimateSparse()
+	}
+
+	//среднее значений регистров
+	sum := 0.0
+	zeros := 0
+	// Проходим по всем регистрам
+	for _, val := range hll.registers {
+		//1 / 2^val вероятность увидеть данный хэш
+		//сумма вероятностей по всем регистрам
+		sum += math.Ldexp(1, -int(val))
+		if val == 0 { // не видел ни одного элемента
+			zeros++
+		}
+	}
+	//Используем гармоническое среднее
+	//если среднее ариф то одна большая оценка испортит всё
+	m := float64(len(hll.registers))
+	estimate := alpha(len(hll.registers)) * m * m / sum
+
+	// до 5m сырая оценка заметно завышена, вычитаем эмпирическое смещение
+	if estimate <= 5*m {
+		estimate -= hllBiasAt(int(hll.p), estimate)
+	}
+
+	// коррекция для малых значений, используется метод
+	// Linear Counting: чем больше нулевых регистров — тем
+	// меньше реальность. Ему верим, пока он ниже порога точности p
+	if zeros != 0 {
+		if lc := m * math.Log(m/float64(zeros)); lc <= hllThresholds[hll.p-MinPrecision] {
+			return lc
+		}
+	}
+	return max(estimate, 0)
+}
+
+// hllBiasK — по скольким ближайшим точкам таблицы усредняется смещение
+const hllBiasK = 6
+
+// hllBiasAt оценивает смещение сырой оценки e как среднее смещений
+// в hllBiasK ближайших к e точках таблицы
+func hllBiasAt(p int, e float64) float64 {
+	raw, bias := hllRawEstimates[p-MinPrecision], hllBias[p-MinPrecision]
+	// сырые оценки в таблице возрастают, ищем место e и расширяем окно
+	// в сторону более близкой соседней точки
+	hi, _ := slices.BinarySearch(raw, e)
+	lo := hi
+	for hi-lo < hllBiasK && hi-lo < len(raw) {
+		if lo > 0 && (hi == len(raw) || e-raw[lo-1] < raw[hi]-e) {
+			lo--
+		} else {
+			hi++
+		}
+	}
+	sum := 0.0
+	for _, b := range bias[lo:hi] {
+		sum += b
+	}
+	return sum / float64(hi-lo)
+}
+
+// alpha — поправочный коэффициент, компенсирующий систематическую ошибку
+func alpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(m))
+	}
+}
+
+// Precision возвращает точность p
+func (hll *HyperLogLog) Precision() int {
+	return int(hll.p)
+}
+
+// StandardError возвращает относительную стандартную ошибку оценки 1.04/sqrt(m)
+func (hll *HyperLogLog) StandardError() float64 {
+	return 1.04 / math.Sqrt(float64(int(1)<<hll.p))
+}
+
+// Memory возвращает размер массива регистров в байтах,
+// а для разреженного счётчика — размер его буферов
+func (hll *HyperLogLog) Memory() int {
+	if hll.registers == nil {
+		return len(hll.sparse) + 4*len(hll.tmp) + 4*len(hll.coupons)
+	}
+	return len(hll.registers)
+}
@@ -0,0 +1,489 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// Совместимость с HLL-скетчами Apache DataSketches (Java и C++, их же
+// используют Spark и Druid). Элемент хэшируется MurmurHash3 x64 128
+// с seed 9001 и превращается в купон — uint32
+//
+//	value<<26 | addr
+//
+// где addr — младшие 26 бит первой половины хэша, а value — число ведущих
+// нулей второй половины плюс один (не больше 63). Регистр купона — младшие
+// lgK бит адреса. Пока купонов мало, DataSketches хранит их списком (LIST)
+// или хэш-таблицей (SET), потом переходит к регистрам (HLL). Образ
+// начинается с преамбулы, числа little-endian:
+//
+//	[0]  preInts: длина преамбулы в 4-байтных словах (2, 3 или 10)
+//	[1]  версия формата, 1
+//	[2]  семейство, 7 — HLL
+//	[3]  lgK
+//	[4]  lgArr: log2 длины массива купонов или таблицы исключений HLL_4
+//	[5]  флаги
+//	[6]  LIST: число купонов; HLL: curMin
+//	[7]  режим (LIST, SET, HLL) | тип регистров<<2
+//
+// В режиме SET за преамбулой идёт uint32 — число купонов, потом купоны.
+// В режиме HLL:
+//
+//	[8:16]   оценка HIP
+//	[16:24]  kxq0 — сумма 2^-v по регистрам с v < 32
+//	[24:32]  kxq1 — то же для v >= 32
+//	[32:36]  число регистров, равных curMin
+//	[36:40]  число исключений HLL_4
+//	[40:..]  регистры, затем исключения HLL_4
+//
+// Регистры HLL_8 занимают байт, HLL_6 упакованы по 6 бит начиная
+// с младших бит, HLL_4 хранят v-curMin по полбайта (чётный регистр —
+// в младшей половине). Значение, не влезающее в 4 бита, заменяется на 15,
+// а сам регистр записывается исключением value<<26 | slot
+const (
+	dsHLLSerVer    = 1
+	dsHLLFamily    = 7
+	dsHLLSeed      = 9001
+	dsHLLAddrBits  = 26
+	dsHLLAddrMask  = 1<<dsHLLAddrBits - 1
+	dsHLLMaxValue  = 63
+	dsHLLAuxToken  = 15
+	dsHLLPreamble  = 8
+	dsHLLArrayFrom = 40
+
+	dsHLLListPreInts = 2
+	dsHLLSetPreInts  = 3
+	dsHLLHLLPreInts  = 10
+
+	dsHLLFlagBigEndian  = 1 << 0
+	dsHLLFlagEmpty      = 1 << 2
+	dsHLLFlagCompact    = 1 << 3
+	dsHLLFlagOutOfOrder = 1 << 4
+
+	dsHLLModeList = 0
+	dsHLLModeSet  = 1
+	dsHLLModeHLL  = 2
+
+	dsHLLLgInitList     = 3
+	dsHLLLgInitSet      = 5
+	dsHLLListMaxCoupons = 1<<dsHLLLgInitList - 1
+)
+
+// dsHLLLgAuxArrInts[lgK] — начальный размер таблицы исключений HLL_4
+var dsHLLLgAuxArrInts = [...]uint8{0, 2, 2, 2, 2, 2, 2, 3, 3, 3, 4, 4, 5, 5, 6, 7, 8, 9, 10, 11, 12, 13}
+
+// DataSketchesType — способ хранения регистров в образе DataSketches
+// (TgtHllType). Оценка от него не зависит, меняется только размер
+type DataSketchesType uint8
+
+const (
+	DataSketchesHLL4 DataSketchesType = iota // 4 бита на регистр и исключения
+	DataSketchesHLL6                         // 6 бит на регистр
+	DataSketchesHLL8                         // байт на регистр
+)
+
+// ErrNotDataSketches — счётчик создан не NewDataSketchesHyperLogLog,
+// и DataSketches его не поймёт
+var ErrNotDataSketches = errors.New("sketch: счётчик HyperLogLog не в режиме DataSketches")
+
+// dataSketchesHasher — MurmurHash3 x64 128 с seed DataSketches
+type dataSketchesHasher struct{}
+
+func (dataSketchesHasher) Sum128(data []byte) (uint64, uint64) {
+	return murmur3Sum128(data, dsHLLSeed)
+}
+
+// NewDataSketchesHyperLogLog создаёт счётчик, совместимый с HllSketch
+// из DataSketches с тем же lgK: строки, добавленные Add, дают те же
+// купоны и регистры, что HllSketch.update(String). lgK от 4 до 18.
+// Пока элементов мало, счётчик хранит купоны, как режимы LIST и SET
+func NewDataSketchesHyperLogLog(lgK int) (*HyperLogLog, error) {
+	if lgK < MinPrecision || lgK > MaxPrecision {
+		return nil, ErrInvalidParams
+	}
+	return newDataSketchesHyperLogLog(uint8(lgK)), nil
+}
+
+func newDataSketchesHyperLogLog(lgK uint8) *HyperLogLog {
+	return &HyperLogLog{
+		p:      lgK,
+		hasher: dataSketchesHasher{},
+		mode:   hllDataSketches,
+	}
+}
+
+// dataSketchesCoupon собирает купон из двух половин хэша
+func dataSketchesCoupon(h1, h2 uint64) uint32 {
+	value := min(bits.LeadingZeros64(h2), dsHLLMaxValue-1) + 1
+	return uint32(value)<<dsHLLAddrBits | uint32(h1)&dsHLLAddrMask
+}
+
+// dataSketchesHashCoupon собирает купон из одного 64-битного хэша для
+// AddHash: младшие 26 бит — адрес, value считается по старшим 38 битам
+func dataSketchesHashCoupon(h uint64) uint32 {
+	value := bits.LeadingZeros64(h|1<<(dsHLLAddrBits-1)) + 1
+	return uint32(value)<<dsHLLAddrBits | uint32(h)&dsHLLAddrMask
+}
+
+// dataSketchesMaxCoupons — сколько купонов DataSketches держит до перехода
+// к регистрам: при lgK < 8 список на 7 купонов, иначе таблица SET
+// размером до 2^(lgK-3), заполненная не больше чем на 3/4
+func dataSketchesMaxCoupons(lgK uint8) int {
+	if lgK < 8 {
+		return dsHLLListMaxCoupons
+	}
+	return 3 << (lgK - 5)
+}
+
+// addCoupon добавляет купон в список или в регистры
+func (hll *HyperLogLog) addCoupon(c uint32) {
+	if hll.registers != nil {
+		hll.setCoupon(c)
+		return
+	}
+	i, found := slices.BinarySearch(hll.coupons, c)
+	if found {
+		return
+	}
+	hll.coupons = slices.Insert(hll.coupons, i, c)
+	if len(hll.coupons) > dataSketchesMaxCoupons(hll.p) {
+		hll.toDense()
+	}
+}
+
+// setCoupon записывает купон в регистры
+func (hll *HyperLogLog) setCoupon(c uint32) {
+	slot := c & (1<<hll.p - 1)
+	hll.registers[slot] = max(hll.registers[slot], byte(c>>dsHLLAddrBits))
+}
+
+// estimateCoupons оценивает кардинальность по числу купонов. Два элемента
+// дают один купон, только если у них совпали и адрес, и значение, поэтому
+// линейный счёт по 2^26 адресам завышал бы оценку: у 196608 купонов,
+// предела при lgK = 21, примерно на 0,1%. Вместо него ищется n, при котором ожидаемое число
+// разных купонов равно числу сохранённых. DataSketches получает ту же
+// величину кубической интерполяцией по таблице, построенной
+// моделированием, так что оценки близки, но побитно не совпадают
+func (hll *HyperLogLog) estimateCoupons() float64 {
+	c := float64(len(hll.coupons))
+	// ожидаемое число купонов вогнуто по n и не больше n, поэтому метод
+	// Ньютона из n = c монотонно подходит к корню слева
+	n := c
+	for i := 0; i < 20; i++ {
+		f, df := dataSketchesExpectedCoupons(n)
+		step := (c - f) / df
+		n += step
+		if step <= 1e-9*n {
+			break
+		}
+	}
+	return n
+}
+
+// dataSketchesExpectedCoupons возвращает ожидаемое число разных купонов
+// после n разных элементов и его производную по n. Значение v выпадает
+// с вероятностью 2^-v, а последнее, 63, — с вероятностью 2^-62
+func dataSketchesExpectedCoupons(n float64) (f, df float64) {
+	m := float64(1 << dsHLLAddrBits)
+	for v := 1; v <= dsHLLMaxValue; v++ {
+		q := math.Ldexp(1, -min(v, dsHLLMaxValue-1))
+		l := math.Log1p(-q / m)
+		miss := math.Exp(n * l) // купон ещё не встречался
+		f += m * (1 - miss)
+		df -= m * miss * l
+	}
+	return f, df
+}
+
+// foldDataSketches уменьшает lgK до p так же, как объединение в DataSketches:
+// значение купона не зависит от адреса, поэтому купоны не меняются,
+// а регистр берёт максимум по регистрам с теми же младшими p битами
+func (hll *HyperLogLog) foldDataSketches(p uint8) {
+	hll.p = p
+	if hll.registers == nil {
+		if len(hll.coupons) > dataSketchesMaxCoupons(p) {
+			hll.toDense()
+		}
+		return
+	}
+	folded := make([]byte, 1<<p)
+	for i, v := range hll.registers {
+		idx := i & (1<<p - 1)
+		folded[idx] = max(folded[idx], v)
+	}
+	hll.registers = folded
+}
+
+// dsHLLArrayBytes возвращает размер массива регистров типа t
+func dsHLLArrayBytes(t DataSketchesType, lgK uint8) int {
+	k := 1 << lgK
+	switch t {
+	case DataSketchesHLL4:
+		return k / 2
+	case DataSketchesHLL6:
+		return k*3/4 + 1
+	default:
+		return k
+	}
+}
+
+// MarshalDataSketches кодирует счётчик компактным образом DataSketches
+// (HllSketch.toCompactByteArray) с регистрами типа t. Пока счётчик хранит
+// купоны, образ в режиме LIST или SET, иначе в режиме HLL. Порядок
+// добавления элементов неизвестен, поэтому образ HLL помечается флагом
+// OUT_OF_ORDER и DataSketches оценивает его по регистрам, а не по HIP
+func (hll *HyperLogLog) MarshalDataSketches(t DataSketchesType) ([]byte, error) {
+	if hll.mode != hllDataSketches {
+		return nil, ErrNotDataSketches
+	}
+	if t > DataSketchesHLL8 {
+		return nil, ErrInvalidParams
+	}
+	buf := []byte{0, dsHLLSerVer, dsHLLFamily, hll.p, 0, dsHLLFlagCompact, 0, byte(t) << 2}
+	n := len(hll.coupons)
+	switch {
+	case hll.registers == nil && n <= dsHLLListMaxCoupons:
+		buf[0], buf[4], buf[6] = dsHLLListPreInts, dsHLLLgInitList, byte(n)
+		buf[7] |= dsHLLModeList
+		if n == 0 {
+			buf[5] |= dsHLLFlagEmpty
+		}
+	case hll.registers == nil:
+		lgArr := uint8(dsHLLLgInitSet)
+		for 4*n > 3<<lgArr {
+			lgArr++
+		}
+		buf[0], buf[4] = dsHLLSetPreInts, lgArr
+		buf[7] |= dsHLLModeSet
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(n))
+	default:
+		return hll.appendDataSketchesHLL(buf, t), nil
+	}
+	for _, c := range hll.coupons {
+		buf = binary.LittleEndian.AppendUint32(buf, c)
+	}
+	return buf, nil
+}
+
+// appendDataSketchesHLL дописывает к преамбуле buf образ в режиме HLL
+func (hll *HyperLogLog) appendDataSketchesHLL(buf []byte, t DataSketchesType) []byte {
+	regs := hll.registers
+	// у HLL_6 и HLL_8 curMin всегда 0, у HLL_4 — наименьший регистр
+	var curMin byte
+	if t == DataSketchesHLL4 {
+		curMin = slices.Min(regs)
+	}
+	numAtCurMin := 0
+	var kxq0, kxq1 float64
+	for _, v := range regs {
+		if v == curMin {
+			numAtCurMin++
+		}
+		if v < 32 {
+			kxq0 += math.Ldexp(1, -int(v))
+		} else {
+			kxq1 += math.Ldexp(1, -int(v))
+		}
+	}
+
+	arr := make([]byte, dsHLLArrayBytes(t, hll.p))
+	var aux []uint32
+	switch t {
+	case DataSketchesHLL4:
+		for i, v := range regs {
+			nib := v - curMin
+			if nib >= dsHLLAuxToken {
+				nib = dsHLLAuxToken
+				aux = append(aux, uint32(v)<<dsHLLAddrBits|uint32(i))
+			}
+			arr[i/2] |= nib << (4 * (i & 1))
+		}
+		lgArr := dsHLLLgAuxArrInts[hll.p]
+		for 4*len(aux) > 3<<lgArr {
+			lgArr++
+		}
+		buf[4] = lgArr
+	case DataSketchesHLL6:
+		for i, v := range regs {
+			pos := i * 6
+			w := uint16(v) << (pos % 8)
+			arr[pos/8] |= byte(w)
+			arr[pos/8+1] |= byte(w >> 8)
+		}
+	default:
+		copy(arr, regs)
+	}
+
+	buf[0] = dsHLLHLLPreInts
+	buf[5] |= dsHLLFlagOutOfOrder
+	buf[6] = curMin
+	buf[7] |= dsHLLModeHLL
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(hll.Estimate()))
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(kxq0))
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(kxq1))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(numAtCurMin))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(aux)))
+	buf = append(buf, arr...)
+	for _, a := range aux {
+		buf = binary.LittleEndian.AppendUint32(buf, a)
+	}
+	return buf
+}
+
+// UnmarshalDataSketches загружает образ HllSketch из DataSketches: компактный
+// или обновляемый, в режиме LIST, SET или HLL, с регистрами HLL_4, HLL_6
+// или HLL_8. Счётчик переходит в режим DataSketches с lgK образа.
+// Поддерживается lgK от 4 до 18. При ошибке счётчик не изменяется
+func (hll *HyperLogLog) UnmarshalDataSketches(data []byte) error {
+	if len(data) < dsHLLPreamble {
+		return fmt.Errorf("%w: данные обрезаны", ErrCorrupt)
+	}
+	if data[2] != dsHLLFamily {
+		return ErrBadMagic
+	}
+	if data[1] != dsHLLSerVer {
+		return fmt.Errorf("%w: версия %d", ErrUnsupportedFormat, data[1])
+	}
+	preInts, lgK, lgArr, flags := data[0], data[3], data[4], data[5]
+	if flags&dsHLLFlagBigEndian != 0 {
+		return fmt.Errorf("%w: big-endian", ErrUnsupportedFormat)
+	}
+	if lgK < MinPrecision || lgK > MaxPrecision {
+		return fmt.Errorf("%w: lgK = %d, поддерживается от %d до %d", ErrUnsupportedFormat, lgK, MinPrecision, MaxPrecision)
+	}
+	if lgArr > dsHLLAddrBits {
+		return fmt.Errorf("%w: lgArr = %d", ErrCorrupt, lgArr)
+	}
+	t := DataSketchesType(data[7] >> 2 & 3)
+	if t > DataSketchesHLL8 {
+		return fmt.Errorf("%w: тип регистров %d", ErrCorrupt, t)
+	}
+	compact := flags&dsHLLFlagCompact != 0
+
+	res := newDataSketchesHyperLogLog(lgK)
+	if flags&dsHLLFlagEmpty != 0 {
+		*hll = *res
+		return nil
+	}
+	var n int
+	var body []byte
+	switch mode := data[7] & 3; {
+	case mode == dsHLLModeList && preInts == dsHLLListPreInts:
+		n, body = int(data[6]), data[dsHLLPreamble:]
+	case mode == dsHLLModeSet && preInts == dsHLLSetPreInts && len(data) >= dsHLLPreamble+4:
+		n, body = int(binary.LittleEndian.Uint32(data[dsHLLPreamble:])), data[dsHLLPreamble+4:]
+	case mode == dsHLLModeHLL && preInts == dsHLLHLLPreInts:
+		if err := res.readDataSketchesHLL(data, t, compact); err != nil {
+			return err
+		}
+		*hll = *res
+		return nil
+	default:
+		return fmt.Errorf("%w: режим %d с преамбулой %d слов", ErrCorrupt, mode, preInts)
+	}
+
+	// в компактном образе купоны идут подряд, в обновляемом лежат
+	// в массиве из 2^lgArr ячеек, пустые ячейки нулевые
+	slots := n
+	if !compact {
+		slots = 1 << lgArr
+	}
+	if len(body) < 4*slots {
+		return fmt.Errorf("%w: данные обрезаны", ErrCorrupt)
+	}
+	found := 0
+	for i := 0; i < slots; i++ {
+		c := binary.LittleEndian.Uint32(body[4*i:])
+		if c == 0 && !compact {
+			continue
+		}
+		if c>>dsHLLAddrBits == 0 {
+			return fmt.Errorf("%w: купон %#x", ErrCorrupt, c)
+		}
+		res.addCoupon(c)
+		found++
+	}
+	if found != n {
+		return fmt.Errorf("%w: в образе %d купонов вместо %d", ErrCorrupt, found, n)
+	}
+	*hll = *res
+	return nil
+}
+
+// readDataSketchesHLL читает регистры образа в режиме HLL
+func (hll *HyperLogLog) readDataSketchesHLL(data []byte, t DataSketchesType, compact bool) error {
+	k := 1 << hll.p
+	end := dsHLLArrayFrom + dsHLLArrayBytes(t, hll.p)
+	if len(data) < end {
+		return fmt.Errorf("%w: данные обрезаны", ErrCorrupt)
+	}
+	arr := data[dsHLLArrayFrom:end]
+	hll.registers = make([]byte, k)
+	switch t {
+	case DataSketchesHLL4:
+		curMin := data[6]
+		aux, err := readDataSketchesAux(data[end:], data, k, compact)
+		if err != nil {
+			return err
+		}
+		for i := range hll.registers {
+			nib := arr[i/2] >> (4 * (i & 1)) & 0xf
+			if nib != dsHLLAuxToken {
+				hll.registers[i] = nib + curMin
+				continue
+			}
+			v, ok := aux[uint32(i)]
+			if !ok {
+				return fmt.Errorf("%w: нет исключения для регистра %d", ErrCorrupt, i)
+			}
+			hll.registers[i] = v
+		}
+	case DataSketchesHLL6:
+		for i := range hll.registers {
+			pos := i * 6
+			w := uint16(arr[pos/8]) | uint16(arr[pos/8+1])<<8
+			hll.registers[i] = byte(w>>(pos%8)) & dsHLLMaxValue
+		}
+	default:
+		copy(hll.registers, arr)
+	}
+	if v := slices.Max(hll.registers); v > dsHLLMaxValue {
+		return fmt.Errorf("%w: значение регистра %d", ErrCorrupt, v)
+	}
+	return nil
+}
+
+// readDataSketchesAux читает исключения HLL_4: регистр -> значение
+func readDataSketchesAux(body, data []byte, k int, compact bool) (map[uint32]byte, error) {
+	count := int(binary.LittleEndian.Uint32(data[36:]))
+	if count == 0 {
+		return nil, nil
+	}
+	slots := count
+	if !compact {
+		slots = 1 << data[4]
+	}
+	if len(body) < 4*slots {
+		return nil, fmt.Errorf("%w: данные обрезаны", ErrCorrupt)
+	}
+	aux := make(map[uint32]byte, count)
+	for i := 0; i < slots; i++ {
+		a := binary.LittleEndian.Uint32(body[4*i:])
+		if a == 0 && !compact {
+			continue
+		}
+		slot := a & dsHLLAddrMask
+		if slot >= uint32(k) {
+			return nil, fmt.Errorf("%w: исключение для регистра %d", ErrCorrupt, slot)
+		}
+		aux[slot] = byte(a >> dsHLLAddrBits)
+	}
+	if len(aux) != count {
+		return nil, fmt.Errorf("%w: в образе %d исключений вместо %d", ErrCorrupt, len(aux), count)
+	}
+	return aux, nil
+}
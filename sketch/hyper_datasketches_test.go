@@ -0,0 +1,283 @@
+package sketch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestDataSketchesCoupons(t *testing.T) {
+	// хэши посчитаны эталонной MurmurHash3_x64_128 на C с seed 9001
+	tests := []struct {
+		in     string
+		h1, h2 uint64
+		coupon uint32
+	}{
+		{"a", 0xf6020f0aa43b822f, 0xc51f4ded6e1eb0fe, 0x043b822f},
+		{"abc", 0xcb57126dd8878d21, 0xef474cf597ded0ee, 0x04878d21},
+		{"hello world", 0x5089a0a6d6a9e5ea, 0x3ce5a47eb7affeff, 0x0ea9e5ea},
+		{"The quick brown fox jumps over the lazy dog", 0x2f67dcdbc56dbf23, 0x8a0a2fafd6b2155c, 0x056dbf23},
+	}
+	for _, tt := range tests {
+		h1, h2 := dataSketchesHasher{}.Sum128([]byte(tt.in))
+		if h1 != tt.h1 || h2 != tt.h2 {
+			t.Errorf("Sum128(%q) = %#x, %#x, want %#x, %#x", tt.in, h1, h2, tt.h1, tt.h2)
+		}
+		if c := dataSketchesCoupon(h1, h2); c != tt.coupon {
+			t.Errorf("купон %q = %#x, want %#x", tt.in, c, tt.coupon)
+		}
+	}
+	if c := dataSketchesCoupon(0, 0); c != 63<<26 {
+		t.Errorf("купон нулевого хэша = %#x, значение должно быть 63", c)
+	}
+}
+
+// Эталонные образы ниже собраны вручную по описанию формата из исходников
+// DataSketches и хэшам эталонной MurmurHash3 на C, а не записаны самой
+// библиотекой: Java и C++ версий под рукой не было. Поэтому они проверяют
+// раскладку байтов так, как её понимает этот пакет. Образы, записанные
+// DataSketches (LIST, SET, HLL_4 с исключениями, HLL_6, HLL_8), стоит
+// добавить сюда, как только появится возможность их получить
+func TestDataSketchesListGolden(t *testing.T) {
+	hll, _ := NewDataSketchesHyperLogLog(12)
+	hll.Add("") // DataSketches пропускает пустые строки
+	empty, _ := hll.MarshalDataSketches(DataSketchesHLL4)
+	if want := []byte{2, 1, 7, 12, 3, 0x0c, 0, 0}; !bytes.Equal(empty, want) {
+		t.Errorf("пустой счётчик: % x, want % x", empty, want)
+	}
+
+	for _, s := range []string{"c", "a", "b", "a"} {
+		hll.Add(s)
+	}
+	golden, _ := hex.DecodeString("0201070c03080308" + "2f823b04" + "0aff8004" + "6f19dc06")
+	got, err := hll.MarshalDataSketches(DataSketchesHLL8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, golden) {
+		t.Fatalf("MarshalDataSketches = % x, want % x", got, golden)
+	}
+	if est := hll.Estimate(); math.Abs(est-3) > 1e-6 {
+		t.Errorf("оценка %v, want 3", est)
+	}
+
+	// обновляемый образ: 2^lgArr ячеек, купоны в произвольных местах
+	updatable := append([]byte{2, 1, 7, 12, 3, 0, 3, 0}, make([]byte, 32)...)
+	for i, c := range []uint32{0x06dc196f, 0x043b822f, 0x0480ff0a} {
+		binary.LittleEndian.PutUint32(updatable[8+4*(2*i+1):], c)
+	}
+	for _, img := range [][]byte{golden, updatable} {
+		var back HyperLogLog
+		if err := back.UnmarshalDataSketches(img); err != nil {
+			t.Fatal(err)
+		}
+		if back.registers != nil || !slices.Equal(back.coupons, hll.coupons) {
+			t.Errorf("купоны после UnmarshalDataSketches: %x, want %x", back.coupons, hll.coupons)
+		}
+	}
+}
+
+func TestDataSketchesSet(t *testing.T) {
+	hll, _ := NewDataSketchesHyperLogLog(12)
+	for i := 0; i < 100; i++ {
+		hll.Add("item-" + strconv.Itoa(i))
+	}
+	if hll.registers != nil {
+		t.Fatal("при lgK = 12 сто купонов помещаются в SET")
+	}
+	if mem := hll.Memory(); mem != 4*100 {
+		t.Errorf("Memory = %d, want %d: считаются только занятые купоны", mem, 4*100)
+	}
+	data, _ := hll.MarshalDataSketches(DataSketchesHLL6)
+	// SET на 2^8 ячеек: 100 купонов заполняют её не больше чем на 3/4
+	if want := []byte{3, 1, 7, 12, 8, 0x08, 0, 0x05, 100, 0, 0, 0}; !bytes.Equal(data[:12], want) {
+		t.Errorf("преамбула % x, want % x", data[:12], want)
+	}
+	if len(data) != 12+4*100 {
+		t.Errorf("длина образа %d", len(data))
+	}
+	var back HyperLogLog
+	if err := back.UnmarshalDataSketches(data); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(back.coupons, hll.coupons) {
+		t.Error("купоны после UnmarshalDataSketches отличаются")
+	}
+	if est := back.Estimate(); math.Abs(est-100) > 0.5 {
+		t.Errorf("оценка %v, want 100", est)
+	}
+
+	// при lgK = 12 таблица растёт до 2^9 ячеек, 385-й купон переводит к регистрам
+	for i := 100; hll.registers == nil; i++ {
+		hll.Add("item-" + strconv.Itoa(i))
+		if hll.registers == nil && len(hll.coupons) > 384 {
+			t.Fatalf("%d купонов без перехода к регистрам", len(hll.coupons))
+		}
+	}
+}
+
+func TestDataSketchesCouponEstimate(t *testing.T) {
+	// 190000 элементов при lgK = 21 ещё хранятся купонами. Совпадений
+	// купонов около 90, разброс их числа — около 10, то есть 5e-5 от n;
+	// линейный счёт по адресам ошибся бы на 9e-4
+	const n = 190_000
+	seed := uint64(3)
+	coupons := make([]uint32, n)
+	for i := range coupons {
+		coupons[i] = dataSketchesHashCoupon(splitmix64(&seed))
+	}
+	slices.Sort(coupons)
+	hll := newDataSketchesHyperLogLog(21)
+	hll.coupons = slices.Compact(coupons)
+	if len(hll.coupons) > dataSketchesMaxCoupons(21) {
+		t.Fatalf("%d купонов не помещаются в SET", len(hll.coupons))
+	}
+	if est := hll.Estimate(); math.Abs(est/n-1) > 3e-4 {
+		t.Errorf("оценка %.0f по %d купонам, want около %d", est, len(hll.coupons), n)
+	}
+}
+
+func TestDataSketchesHLLHandmade(t *testing.T) {
+	// lgK = 4: 16 регистров, curMin = 1, регистр 5 — исключение
+	regs := []byte{1, 2, 3, 4, 5, 40, 1, 1, 15, 15, 1, 1, 1, 1, 1, 9}
+	hll := newDataSketchesHyperLogLog(4)
+	hll.registers = regs
+
+	hll4, _ := hll.MarshalDataSketches(DataSketchesHLL4)
+	wantHead := []byte{10, 1, 7, 4, 2, 0x18, 1, 0x02}
+	wantTail, _ := hex.DecodeString("08000000" + "01000000" + "10" + "32" + "f4" + "00" + "ee" + "00" + "00" + "80" + "050000a0")
+	if !bytes.Equal(hll4[:8], wantHead) || !bytes.Equal(hll4[32:], wantTail) {
+		t.Errorf("HLL_4: % x ... % x", hll4[:8], hll4[32:])
+	}
+
+	hll6, _ := hll.MarshalDataSketches(DataSketchesHLL6)
+	if want, _ := hex.DecodeString("813010"); !bytes.Equal(hll6[40:43], want) || len(hll6) != 40+13 {
+		t.Errorf("HLL_6: % x", hll6[40:])
+	}
+	hll8, _ := hll.MarshalDataSketches(DataSketchesHLL8)
+	if !bytes.Equal(hll8[40:], regs) || hll8[6] != 0 || binary.LittleEndian.Uint32(hll8[32:]) != 0 {
+		t.Errorf("HLL_8: % x", hll8)
+	}
+
+	for _, img := range [][]byte{hll4, hll6, hll8} {
+		var back HyperLogLog
+		if err := back.UnmarshalDataSketches(img); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(back.registers, regs) {
+			t.Errorf("тип %d: регистры % x, want % x", img[7]>>2, back.registers, regs)
+		}
+	}
+
+	// обновляемый HLL_4: исключения в таблице на 2^lgArr ячеек
+	updatable := append(hll4[:len(hll4)-4:len(hll4)-4], make([]byte, 16)...)
+	updatable[5] &^= dsHLLFlagCompact
+	binary.LittleEndian.PutUint32(updatable[len(updatable)-8:], 40<<26|5)
+	var back HyperLogLog
+	if err := back.UnmarshalDataSketches(updatable); err != nil || !bytes.Equal(back.registers, regs) {
+		t.Errorf("обновляемый HLL_4: %v, регистры % x", err, back.registers)
+	}
+}
+
+func TestDataSketchesHLLRoundTrip(t *testing.T) {
+	const n = 100_000
+	hll, _ := NewDataSketchesHyperLogLog(11)
+	for i := 0; i < n; i++ {
+		hll.Add("element-" + strconv.Itoa(i))
+	}
+	if hll.registers == nil {
+		t.Fatal("счётчик должен перейти к регистрам")
+	}
+	if est := hll.Estimate(); math.Abs(est-n) > 4*hll.StandardError()*n {
+		t.Errorf("оценка %.0f, want около %d", est, n)
+	}
+	for _, typ := range []DataSketchesType{DataSketchesHLL4, DataSketchesHLL6, DataSketchesHLL8} {
+		data, err := hll.MarshalDataSketches(typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var back HyperLogLog
+		if err := back.UnmarshalDataSketches(data); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(back.registers, hll.registers) || back.Precision() != 11 {
+			t.Errorf("тип %d: регистры после UnmarshalDataSketches отличаются", typ)
+		}
+	}
+}
+
+func TestDataSketchesMerge(t *testing.T) {
+	build := func(lgK int, from, to int) *HyperLogLog {
+		hll, _ := NewDataSketchesHyperLogLog(lgK)
+		for i := from; i < to; i++ {
+			hll.Add("element-" + strconv.Itoa(i))
+		}
+		return hll
+	}
+	tests := []struct {
+		name         string
+		la, lb, want int
+		na, nb       int
+	}{
+		{"купоны", 12, 12, 12, 50, 60},
+		{"регистры", 12, 12, 12, 20_000, 20_000},
+		{"купоны в регистры", 12, 12, 12, 20_000, 50},
+		{"свёртка", 10, 12, 10, 20_000, 20_000},
+		{"свёртка купонов", 12, 8, 8, 20, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := build(tt.la, 0, tt.na)
+			if err := a.Merge(build(tt.lb, tt.na/2, tt.na/2+tt.nb)); err != nil {
+				t.Fatal(err)
+			}
+			direct := build(tt.want, 0, max(tt.na, tt.na/2+tt.nb))
+			a.toDense()
+			direct.toDense()
+			if a.Precision() != tt.want || !bytes.Equal(a.registers, direct.registers) {
+				t.Error("регистры объединения отличаются от прямого подсчёта")
+			}
+		})
+	}
+	std, _ := NewHyperLogLog(12)
+	if err := build(12, 0, 10).Merge(std); !errors.Is(err, ErrHLLIncompatible) {
+		t.Errorf("Merge с обычным счётчиком: %v", err)
+	}
+}
+
+func TestDataSketchesErrors(t *testing.T) {
+	std, _ := NewHyperLogLog(12)
+	if _, err := std.MarshalDataSketches(DataSketchesHLL8); !errors.Is(err, ErrNotDataSketches) {
+		t.Errorf("MarshalDataSketches обычного счётчика: %v", err)
+	}
+	list := "0201070c03080308" + "2f823b04" + "0aff8004" + "6f19dc06"
+	hll4 := "0a01070402180102" + "0000000000000000" + "0000000000000000" + "0000000000000000" +
+		"08000000" + "01000000" + "1032f400ee000080" + "070000a0"
+	tests := []struct {
+		name, data string
+		want       error
+	}{
+		{"короткий", "020107", ErrCorrupt},
+		{"семейство", "020108" + list[6:], ErrBadMagic},
+		{"версия", "020207" + list[6:], ErrUnsupportedFormat},
+		{"lgK", "02010715" + list[8:], ErrUnsupportedFormat},
+		{"big-endian", "0201070c0309" + list[12:], ErrUnsupportedFormat},
+		{"купоны обрезаны", list[:len(list)-2], ErrCorrupt},
+		{"нулевой купон", list[:16] + "00000000" + list[24:], ErrCorrupt},
+		{"режим", "0301070c03080308" + list[16:], ErrCorrupt},
+		{"нет исключения", hll4, ErrCorrupt},
+		{"регистры обрезаны", hll4[:84], ErrCorrupt},
+	}
+	for _, tt := range tests {
+		data, _ := hex.DecodeString(tt.data)
+		var hll HyperLogLog
+		if err := hll.UnmarshalDataSketches(data); !errors.Is(err, tt.want) {
+			t.Errorf("%s: %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
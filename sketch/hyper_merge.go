@@ -0,0 +1,152 @@
+package sketch
+
+import (
+	"errors"
+	"math/bits"
+	"slices"
+)
+
+// ErrHLLIncompatible — счётчики с разными хэш-функциями нельзя объединять:
+// один и тот же элемент попадёт в них в разные регистры
+var ErrHLLIncompatible = errors.New("sketch: счётчики HyperLogLog с разными хэш-функциями")
+
+// clone возвращает независимую копию счётчика
+func (hll *HyperLogLog) clone() *HyperLogLog {
+	c := *hll
+	c.registers = slices.Clone(hll.registers)
+	c.sparse = slices.Clone(hll.sparse)
+	c.tmp = slices.Clone(hll.tmp)
+	c.coupons = slices.Clone(hll.coupons)
+	return &c
+}
+
+// entries возвращает отсортированные элементы разреженного счётчика вместе с tmp
+func (hll *HyperLogLog) entries() []uint32 {
+	return mergeSparse(decodeSparse(hll.sparse), slices.Sorted(slices.Values(hll.tmp)))
+}
+
+// foldRho пересчитывает rho при уменьшении точности на d бит: младшие
+// d бит старого индекса становятся первыми битами, по которым считается
+// rho. Если среди них есть единица, rho определяется ею, иначе к старому
+// rho прибавляется d
+func foldRho(low uint32, d uint8, rho byte) byte {
+	if low != 0 {
+		return d - byte(bits.Len32(low)) + 1
+	}
+	return d + rho
+}
+
+// fold уменьшает точность счётчика до p. Результат совпадает со счётчиком
+// точности p, в который добавили те же элементы
+func (hll *HyperLogLog) fold(p uint8) {
+	d := hll.p - p
+	if d == 0 {
+		return
+	}
+	if hll.mode == hllDataSketches {
+		hll.foldDataSketches(p)
+		return
+	}
+	if hll.registers == nil {
+		es := hll.entries()
+		for i, e := range es {
+			low := e >> hllSparseRhoBits >> (hllSparsePrecision - hll.p) & (1<<d - 1)
+			rho := foldRho(low, d, byte(e&(1<<hllSparseRhoBits-1)))
+			es[i] = e&^(1<<hllSparseRhoBits-1) | uint32(rho)
+		}
+		hll.p = p
+		hll.sparse = encodeSparse(hll.sparse[:0], es)
+		hll.tmp = nil
+		if hll.Memory() > 1<<p {
+			hll.toDense()
+		}
+		return
+	}
+	folded := make([]byte, 1<<p)
+	for i, rho := range hll.registers {
+		if rho == 0 {
+			continue
+		}
+		idx := i >> d
+		folded[idx] = max(folded[idx], foldRho(uint32(i)&(1<<d-1), d, rho))
+	}
+	hll.registers = folded
+	hll.p = p
+}
+
+// Merge добавляет в hll все элементы other (регистр за регистром берётся
+// максимум). Если точности разные, более точный счётчик сворачивается
+// до меньшей точности: other при этом не меняется, а hll может потерять
+// точность. Разреженные счётчики сливаются без перехода к регистрам
+func (hll *HyperLogLog) Merge(other *HyperLogLog) error {
+	if !sameHasher(hll.hasher, other.hasher) || hll.mode != other.mode {
+		return ErrHLLIncompatible
+	}
+	if other.p > hll.p {
+		other = other.clone()
+		other.fold(hll.p)
+	}
+	hll.fold(other.p)
+
+	if other.mode == hllDataSketches && other.registers == nil {
+		for _, c := range other.coupons {
+			hll.addCoupon(c)
+		}
+		return nil
+	}
+
+	switch {
+	case other.registers != nil:
+		hll.toDense()
+		for i, rho := range other.registers {
+			hll.registers[i] = max(hll.registers[i], rho)
+		}
+	case hll.registers == nil:
+		hll.sparse = encodeSparse(nil, mergeSparse(hll.entries(), other.entries()))
+		hll.tmp = nil
+		if hll.Memory() > 1<<hll.p {
+			hll.toDense()
+		}
+	default:
+		for _, e := range other.entries() {
+			idx := e >> hllSparseRhoBits >> (hllSparsePrecision - hll.p)
+			hll.registers[idx] = max(hll.registers[idx], byte(e&(1<<hllSparseRhoBits-1)))
+		}
+	}
+	return nil
+}
+
+// Union возвращает новый счётчик — объединение hll и other
+func (hll *HyperLogLog) Union(other *HyperLogLog) (*HyperLogLog, error) {
+	res := hll.clone()
+	if err := res.Merge(other); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// IntersectionEstimate оценивает мощность пересечения по формуле
+// включений-исключений |A ∩ B| = |A| + |B| - |A ∪ B|. Абсолютная ошибка
+// порядка ошибки оценки объединения, поэтому для маленьких пересечений
+// больших множеств относительная ошибка велика
+func (hll *HyperLogLog) IntersectionEstimate(other *HyperLogLog) (float64, error) {
+	union, err := hll.Union(other)
+	if err != nil {
+		return 0, err
+	}
+	return max(hll.Estimate()+other.Estimate()-union.Estimate(), 0), nil
+}
+
+// JaccardEstimate оценивает коэффициент Жаккара |A ∩ B| / |A ∪ B|
+func (hll *HyperLogLog) JaccardEstimate(other *HyperLogLog) (float64, error) {
+	union, err := hll.Union(other)
+	if err != nil {
+		return 0, err
+	}
+	u := union.Estimate()
+	if u == 0 {
+		return 0, nil
+	}
+	inter := max(hll.Estimate()+other.Estimate()-u, 0)
+	return min(inter/u, 1), nil
+}
@@ -0,0 +1,101 @@
+package sketch
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+// hllFromHashes строит счётчик точности p по хэшам из [from, to)
+func hllFromHashes(p int, from, to uint64, dense bool) *HyperLogLog {
+	hll, _ := NewHyperLogLog(p)
+	if dense {
+		hll.toDense()
+	}
+	for i := from; i < to; i++ {
+		hll.AddHash(murmurFmix(i))
+	}
+	return hll
+}
+
+func TestHyperLogLogMergeMatchesDirect(t *testing.T) {
+	// объединение должно дать ровно те же регистры, что и счётчик,
+	// в который сразу добавили оба множества, в том числе при свёртке
+	// и для разреженных счётчиков
+	tests := []struct {
+		name         string
+		pa, pb, want int
+		na, nb       uint64
+	}{
+		{"плотные", 12, 12, 12, 50_000, 50_000},
+		{"разреженные", 12, 12, 12, 100, 150},
+		{"разреженный в плотный", 12, 12, 12, 50_000, 100},
+		{"свёртка другого", 10, 14, 10, 50_000, 50_000},
+		{"свёртка своего", 14, 10, 10, 50_000, 50_000},
+		{"свёртка разреженного", 10, 14, 10, 100, 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := hllFromHashes(tt.pa, 0, tt.na, false)
+			b := hllFromHashes(tt.pb, tt.na/2, tt.na/2+tt.nb, false)
+			bBefore := b.clone()
+			if err := a.Merge(b); err != nil {
+				t.Fatal(err)
+			}
+			if a.Precision() != tt.want {
+				t.Fatalf("Precision = %d, want %d", a.Precision(), tt.want)
+			}
+			if b.p != bBefore.p || !bytes.Equal(b.registers, bBefore.registers) || !bytes.Equal(b.sparse, bBefore.sparse) {
+				t.Fatal("Merge изменил аргумент")
+			}
+			direct := hllFromHashes(tt.want, 0, max(tt.na, tt.na/2+tt.nb), false)
+			a.toDense()
+			direct.toDense()
+			if !bytes.Equal(a.registers, direct.registers) {
+				t.Fatal("регистры объединения отличаются от прямого подсчёта")
+			}
+		})
+	}
+}
+
+func TestHyperLogLogSetOperations(t *testing.T) {
+	const p = 14
+	tests := []struct{ na, nb, overlap uint64 }{
+		{1_000_000, 1_000_000, 500_000},
+		{200_000, 800_000, 100_000},
+		{1_000_000, 1_000_000, 0},
+		{300_000, 300_000, 300_000},
+	}
+	for _, tt := range tests {
+		a := hllFromHashes(p, 0, tt.na, true)
+		b := hllFromHashes(p, tt.na-tt.overlap, tt.na-tt.overlap+tt.nb, true)
+		union := float64(tt.na + tt.nb - tt.overlap)
+		inter := float64(tt.overlap)
+		se := a.StandardError()
+
+		u, err := a.Union(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := u.Estimate(); math.Abs(got-union) > 4*se*union {
+			t.Errorf("%+v: объединение %.0f, точно %.0f", tt, got, union)
+		}
+		// ошибка пересечения складывается из ошибок трёх оценок
+		tol := 4 * se * (float64(tt.na) + float64(tt.nb) + union)
+		if got, _ := a.IntersectionEstimate(b); math.Abs(got-inter) > tol {
+			t.Errorf("%+v: пересечение %.0f, точно %.0f", tt, got, inter)
+		}
+		if got, _ := a.JaccardEstimate(b); math.Abs(got-inter/union) > tol/union {
+			t.Errorf("%+v: Жаккар %.4f, точно %.4f", tt, got, inter/union)
+		}
+	}
+}
+
+func TestHyperLogLogMergeHasher(t *testing.T) {
+	a, _ := NewHyperLogLog(10)
+	b, _ := NewHyperLogLog(10)
+	b.SetHasher(XXHash)
+	if err := a.Merge(b); err != ErrHLLIncompatible {
+		t.Errorf("Merge с другим хэшером: %v", err)
+	}
+}
@@ -0,0 +1,224 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// Совместимость с HyperLogLog в Redis (hyperloglog.c). Redis хэширует
+// элемент MurmurHash64A с seed 0xadc83b19, младшие 14 бит хэша выбирают
+// один из 16384 регистров, а rho — номер первой единицы в оставшихся
+// 50 битах, считая от младшего. Значение хранится строкой:
+//
+//	[0:4]   магическое число "HYLL"
+//	[4]     кодировка: 0 — плотная, 1 — разреженная
+//	[5:8]   не используются (нули)
+//	[8:16]  кэш кардинальности, little-endian; старший бит [15] —
+//	        признак, что кэш устарел
+//	[16:..] регистры
+//
+// В плотной кодировке регистры упакованы по 6 бит начиная с младших бит
+// каждого байта. Разреженная кодировка — последовательность команд:
+//
+//	00xxxxxx          ZERO:  xxxxxx+1 нулевых регистров (1..64)
+//	01xxxxxx yyyyyyyy XZERO: xxxxxxyyyyyyyy+1 нулевых регистров (1..16384)
+//	1vvvvvxx          VAL:   xx+1 регистров (1..4) со значением vvvvv+1 (1..32)
+const (
+	redisHLLP              = 14
+	redisHLLRegisters      = 1 << redisHLLP
+	redisHLLSeed           = 0xadc83b19
+	redisHLLMagic          = "HYLL"
+	redisHLLHeaderSize     = 16
+	redisHLLDenseSize      = redisHLLHeaderSize + redisHLLRegisters*6/8
+	redisHLLDense          = 0
+	redisHLLSparse         = 1
+	redisHLLSparseMaxBytes = 3000 // hll-sparse-max-bytes по умолчанию
+
+	redisSparseZeroMax  = 64
+	redisSparseXZeroMax = 16384
+	redisSparseValMax   = 32
+	redisSparseValLen   = 4
+)
+
+// ErrNotRedis — счётчик создан не NewRedisHyperLogLog, и Redis его не поймёт
+var ErrNotRedis = errors.New("sketch: счётчик HyperLogLog не в режиме Redis")
+
+// redisHasher — MurmurHash64A, как в Redis. Вторая половина не нужна
+type redisHasher struct{}
+
+func (redisHasher) Sum128(data []byte) (uint64, uint64) {
+	return murmurHash64A(data, redisHLLSeed), 0
+}
+
+// murmurHash64A — 64-битный MurmurHash2 (Austin Appleby) в варианте
+// Redis: хвост читается побайтно, поэтому результат не зависит от
+// порядка байт машины
+func murmurHash64A(data []byte, seed uint64) uint64 {
+	const m = 0xc6a4a7935bd1e995
+	const r = 47
+	h := seed ^ uint64(len(data))*m
+	for ; len(data) >= 8; data = data[8:] {
+		k := binary.LittleEndian.Uint64(data)
+		k *= m
+		k ^= k >> r
+		k *= m
+		h ^= k
+		h *= m
+	}
+	if len(data) > 0 {
+		for i := len(data) - 1; i >= 0; i-- {
+			h ^= uint64(data[i]) << (8 * i)
+		}
+		h *= m
+	}
+	h ^= h >> r
+	h *= m
+	h ^= h >> r
+	return h
+}
+
+// NewRedisHyperLogLog создаёт счётчик, регистры которого совпадают
+// с регистрами Redis для тех же элементов: p = 14, хэш MurmurHash64A.
+// Такой счётчик всегда плотный и объединяется только с такими же
+func NewRedisHyperLogLog() *HyperLogLog {
+	return &HyperLogLog{
+		registers: make([]byte, redisHLLRegisters),
+		p:         redisHLLP,
+		hasher:    redisHasher{},
+		mode:      hllRedis,
+	}
+}
+
+// redisPattern возвращает регистр и rho хэша так, как это делает
+// hllPatLen в Redis. Единица-ограничитель в бите 50 даёт rho не больше 51
+func redisPattern(h uint64) (uint64, byte) {
+	idx := h & (redisHLLRegisters - 1)
+	rho := byte(bits.TrailingZeros64(h>>redisHLLP|1<<(64-redisHLLP))) + 1
+	return idx, rho
+}
+
+// MarshalRedis кодирует счётчик строкой Redis, которую можно записать
+// командой SET и читать PFCOUNT. Как и Redis, использует разреженную
+// кодировку, пока вся строка вместе с заголовком не длиннее
+// hll-sparse-max-bytes, иначе плотную.
+// Кэш кардинальности помечается устаревшим, Redis пересчитает его сам
+func (hll *HyperLogLog) MarshalRedis() ([]byte, error) {
+	if hll.mode != hllRedis {
+		return nil, ErrNotRedis
+	}
+	buf := make([]byte, redisHLLHeaderSize, redisHLLDenseSize)
+	copy(buf[0:4], redisHLLMagic)
+	buf[15] = 1 << 7
+
+	if sparse, ok := appendRedisSparse(buf, hll.registers); ok && len(sparse) <= redisHLLSparseMaxBytes {
+		sparse[4] = redisHLLSparse
+		return sparse, nil
+	}
+	buf[4] = redisHLLDense
+	// неудачная попытка разреженной кодировки могла оставить байты в buf
+	buf = buf[:redisHLLDenseSize]
+	clear(buf[redisHLLHeaderSize:])
+	for i, v := range hll.registers {
+		pos := i * 6
+		b, fb := redisHLLHeaderSize+pos/8, uint(pos%8)
+		buf[b] |= v << fb
+		if fb > 2 {
+			buf[b+1] |= v >> (8 - fb)
+		}
+	}
+	return buf, nil
+}
+
+// appendRedisSparse дописывает регистры командами ZERO, XZERO и VAL.
+// Возвращает false, если есть значение больше 32: VAL его не вместит
+func appendRedisSparse(dst []byte, registers []byte) ([]byte, bool) {
+	for i := 0; i < len(registers); {
+		v := registers[i]
+		j := i + 1
+		if v == 0 {
+			for j < len(registers) && registers[j] == 0 && j-i < redisSparseXZeroMax {
+				j++
+			}
+			if n := j - i; n > redisSparseZeroMax {
+				dst = append(dst, 0x40|byte((n-1)>>8), byte(n-1))
+			} else {
+				dst = append(dst, byte(n-1))
+			}
+		} else {
+			if v > redisSparseValMax {
+				return nil, false
+			}
+			for j < len(registers) && registers[j] == v && j-i < redisSparseValLen {
+				j++
+			}
+			dst = append(dst, 0x80|(v-1)<<2|byte(j-i-1))
+		}
+		i = j
+	}
+	return dst, true
+}
+
+// UnmarshalRedis загружает строку HYLL, полученную из Redis командой GET,
+// в плотной или разреженной кодировке. Счётчик переходит в режим Redis.
+// При ошибке счётчик не изменяется
+func (hll *HyperLogLog) UnmarshalRedis(data []byte) error {
+	if len(data) < redisHLLHeaderSize {
+		return fmt.Errorf("%w: данные обрезаны", ErrCorrupt)
+	}
+	if string(data[0:4]) != redisHLLMagic {
+		return ErrBadMagic
+	}
+	res := NewRedisHyperLogLog()
+	body := data[redisHLLHeaderSize:]
+	switch data[4] {
+	case redisHLLDense:
+		if len(data) != redisHLLDenseSize {
+			return fmt.Errorf("%w: плотная кодировка длиной %d байт", ErrCorrupt, len(data))
+		}
+		for i := range res.registers {
+			pos := i * 6
+			b, fb := pos/8, uint(pos%8)
+			v := uint(body[b]) >> fb
+			if fb > 2 {
+				v |= uint(body[b+1]) << (8 - fb)
+			}
+			res.registers[i] = byte(v & 63)
+		}
+	case redisHLLSparse:
+		idx := 0
+		for i := 0; i < len(body); i++ {
+			op := body[i]
+			var n int
+			var v byte
+			switch {
+			case op&0xc0 == 0x00: // ZERO
+				n = int(op&0x3f) + 1
+			case op&0xc0 == 0x40: // XZERO
+				if i+1 == len(body) {
+					return fmt.Errorf("%w: команда XZERO обрезана", ErrCorrupt)
+				}
+				n = (int(op&0x3f)<<8 | int(body[i+1])) + 1
+				i++
+			default: // VAL
+				n = int(op&0x03) + 1
+				v = (op>>2)&0x1f + 1
+			}
+			if idx+n > redisHLLRegisters {
+				return fmt.Errorf("%w: разреженная кодировка длиннее %d регистров", ErrCorrupt, redisHLLRegisters)
+			}
+			for k := 0; k < n; k++ {
+				res.registers[idx+k] = v
+			}
+			idx += n
+		}
+		if idx != redisHLLRegisters {
+			return fmt.Errorf("%w: разреженная кодировка описывает %d регистров из %d", ErrCorrupt, idx, redisHLLRegisters)
+		}
+	default:
+		return fmt.Errorf("%w: кодировка HYLL %d", ErrUnsupportedFormat, data[4])
+	}
+	*hll = *res
+	return nil
+}
@@ -0,0 +1,163 @@
+package sketch
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"testing"
+)
+
+// Эталоны посчитаны отдельной программой на C по hyperloglog.c из Redis
+
+func TestMurmurHash64AVectors(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint64
+	}{
+		{"", 0xd8dfea6585bc9732},
+		{"a", 0x53d2470a9b43b1a7},
+		{"abc", 0x77ec90aeb374e502},
+		{"hello world", 0xa919bc3051f624b7},
+		{"The quick brown fox jumps over the lazy dog", 0x51606c5c5b561ace},
+	}
+	for _, tt := range tests {
+		if got := murmurHash64A([]byte(tt.in), redisHLLSeed); got != tt.want {
+			t.Errorf("murmurHash64A(%q) = %#x, want %#x", tt.in, got, tt.want)
+		}
+	}
+}
+
+// redisHeader — заголовок HYLL с устаревшим кэшем, как после PFADD
+func redisHeader(encoding byte) string {
+	return "HYLL" + string([]byte{encoding, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x80})
+}
+
+func TestRedisHyperLogLogSparseGolden(t *testing.T) {
+	hll := NewRedisHyperLogLog()
+	empty, _ := hll.MarshalRedis()
+	if want := redisHeader(1) + "\x7f\xff"; string(empty) != want {
+		t.Errorf("пустой счётчик: %x, want %x", empty, want)
+	}
+
+	// PFADD k a b c foo bar
+	regs := map[int]byte{12711: 2, 15780: 1, 8436: 1, 7348: 5, 10007: 1}
+	for _, s := range []string{"a", "b", "c", "foo", "bar"} {
+		hll.Add(s)
+	}
+	for i, v := range hll.registers {
+		if v != regs[i] {
+			t.Fatalf("регистр %d = %d, want %d", i, v, regs[i])
+		}
+	}
+	ops, _ := hex.DecodeString("5cb390443e804621804a8e844bfb80425a")
+	golden := append([]byte(redisHeader(1)), ops...)
+	got, err := hll.MarshalRedis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, golden) {
+		t.Fatalf("MarshalRedis = %x, want %x", got, golden)
+	}
+
+	var back HyperLogLog
+	if err := back.UnmarshalRedis(golden); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(back.registers, hll.registers) {
+		t.Error("регистры после UnmarshalRedis отличаются")
+	}
+	// только что созданный ключ: кэш действителен и равен нулю
+	fresh := []byte("HYLL\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x7f\xff")
+	if err := back.UnmarshalRedis(fresh); err != nil || back.Estimate() != 0 {
+		t.Errorf("пустой ключ Redis: %v, оценка %v", err, back.Estimate())
+	}
+}
+
+func TestRedisHyperLogLogSparseLimit(t *testing.T) {
+	// Redis сравнивает с hll-sparse-max-bytes длину всей строки: заголовок,
+	// 1490 пар VAL и ZERO по байту, два VAL подряд и XZERO на остаток
+	// дают ровно 3000
+	hll := NewRedisHyperLogLog()
+	for i := 0; i < 1491; i++ {
+		hll.registers[2*i] = 1
+	}
+	hll.registers[2981] = 2
+	data, _ := hll.MarshalRedis()
+	if len(data) != redisHLLSparseMaxBytes || data[4] != redisHLLSparse {
+		t.Errorf("3000 байт: длина %d, кодировка %d, want разреженная", len(data), data[4])
+	}
+	// ещё один VAL — 3001 байт, Redis перешёл бы к плотной кодировке
+	hll.registers[2982] = 3
+	data, _ = hll.MarshalRedis()
+	if len(data) != redisHLLDenseSize || data[4] != redisHLLDense {
+		t.Errorf("3001 байт: длина %d, кодировка %d, want плотная", len(data), data[4])
+	}
+}
+
+func TestRedisHyperLogLogDenseGolden(t *testing.T) {
+	const n = 100_000
+	hll := NewRedisHyperLogLog()
+	for i := 0; i < n; i++ {
+		hll.Add(fmt.Sprintf("element-%d", i))
+	}
+	data, err := hll.MarshalRedis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != redisHLLDenseSize || string(data[:16]) != redisHeader(0) {
+		t.Fatalf("заголовок %x, длина %d", data[:16], len(data))
+	}
+	head, _ := hex.DecodeString("497110c75014c24010c34010c2301006600884312c864008")
+	if !bytes.Equal(data[16:16+len(head)], head) {
+		t.Errorf("первые регистры %x, want %x", data[16:16+len(head)], head)
+	}
+	h := fnv.New64a()
+	h.Write(data[16:])
+	if got := h.Sum64(); got != 0xb99beb09dca7177a {
+		t.Errorf("FNV-1a регистров = %#x, want 0xb99beb09dca7177a", got)
+	}
+
+	var back HyperLogLog
+	if err := back.UnmarshalRedis(data); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(back.registers, hll.registers) {
+		t.Error("регистры после UnmarshalRedis отличаются")
+	}
+	if got := back.Estimate(); math.Abs(got-n) > 4*back.StandardError()*n {
+		t.Errorf("оценка %.0f, want около %d", got, n)
+	}
+}
+
+func TestRedisHyperLogLogErrors(t *testing.T) {
+	var hll HyperLogLog
+	if _, err := (&HyperLogLog{p: 14}).MarshalRedis(); !errors.Is(err, ErrNotRedis) {
+		t.Errorf("MarshalRedis обычного счётчика: %v", err)
+	}
+	tests := []struct {
+		data string
+		want error
+	}{
+		{"HYL", ErrCorrupt},
+		{"HYLX" + redisHeader(1)[4:] + "\x7f\xff", ErrBadMagic},
+		{redisHeader(2), ErrUnsupportedFormat},
+		{redisHeader(0) + "\x00", ErrCorrupt},
+		{redisHeader(1) + "\x7f\xfe", ErrCorrupt},     // на регистр меньше
+		{redisHeader(1) + "\x7f\xff\x80", ErrCorrupt}, // на регистр больше
+		{redisHeader(1) + "\x7f", ErrCorrupt},         // XZERO обрезана
+	}
+	for _, tt := range tests {
+		if err := hll.UnmarshalRedis([]byte(tt.data)); !errors.Is(err, tt.want) {
+			t.Errorf("UnmarshalRedis(%x) = %v, want %v", tt.data, err, tt.want)
+		}
+	}
+	a, b := NewRedisHyperLogLog(), &HyperLogLog{}
+	*b = *NewRedisHyperLogLog()
+	b.mode = hllStandard
+	if err := a.Merge(b); !errors.Is(err, ErrHLLIncompatible) {
+		t.Errorf("Merge Redis с обычным: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"math"
+	"slices"
+)
+
+// Разреженное представление HyperLogLog++ (Heule и др., 2013). Пока
+// элементов мало, вместо 2^p регистров хранится отсортированный список
+// пар (индекс, rho), где индекс берётся с повышенной точностью
+// p' = 25 бит хэша. Элемент списка — uint32
+//
+//	idx'<<6 | rho
+//
+// rho здесь тот же, что посчитан для плотных регистров точности p,
+// поэтому при переходе к регистрам регистр idx'>>(p'-p) просто получает
+// наибольший rho своих элементов и совпадает с тем, что дал бы плотный
+// счётчик. Список хранится разностями соседних элементов в varint;
+// новые элементы копятся в tmp и сливаются пачками. Когда список
+// вместе с tmp становится больше плотного массива (m байт), счётчик
+// переходит к регистрам
+const (
+	hllSparsePrecision = 25
+	hllSparseRhoBits   = 6 // rho <= 64-p+1 <= 61
+)
+
+// addSparse добавляет элемент в разреженный счётчик
+func (hll *HyperLogLog) addSparse(h uint64, rho byte) {
+	hll.tmp = append(hll.tmp, uint32(h>>(64-hllSparsePrecision))<<hllSparseRhoBits|uint32(rho))
+	// tmp растёт от нуля и занимает не больше четверти плотного массива,
+	// поэтому счётчик с несколькими элементами занимает десятки байт
+	if len(hll.tmp) >= max(1, 1<<hll.p/16) {
+		hll.flushSparse()
+	}
+}
+
+// flushSparse сливает tmp в sparse и при необходимости переводит
+// счётчик в плотный вид
+func (hll *HyperLogLog) flushSparse() {
+	if len(hll.tmp) == 0 {
+		return
+	}
+	slices.Sort(hll.tmp)
+	entries := mergeSparse(decodeSparse(hll.sparse), hll.tmp)
+	hll.tmp = nil
+	hll.sparse = encodeSparse(hll.sparse[:0], entries)
+	if hll.Memory() > 1<<hll.p {
+		hll.toDense()
+	}
+}
+
+// toDense переводит счётчик к плотным регистрам
+func (hll *HyperLogLog) toDense() {
+	if hll.registers != nil {
+		return
+	}
+	entries := mergeSparse(decodeSparse(hll.sparse), slices.Sorted(slices.Values(hll.tmp)))
+	hll.registers = make([]byte, 1<<hll.p)
+	for _, e := range entries {
+		idx := e >> hllSparseRhoBits >> (hllSparsePrecision - hll.p)
+		hll.registers[idx] = max(hll.registers[idx], byte(e&(1<<hllSparseRhoBits-1)))
+	}
+	for _, c := range hll.coupons {
+		hll.setCoupon(c)
+	}
+	hll.sparse, hll.tmp, hll.coupons = nil, nil, nil
+}
+
+// estimateSparse оценивает кардинальность линейным счётом по 2^p'
+// ячейкам: при небольшом числе элементов он точнее HyperLogLog
+func (hll *HyperLogLog) estimateSparse() float64 {
+	hll.flushSparse()
+	if hll.registers != nil {
+		return hll.Estimate()
+	}
+	n := 0
+	for rest := hll.sparse; len(rest) > 0; n++ {
+		_, k := binary.Uvarint(rest)
+		rest = rest[k:]
+	}
+	m := float64(int(1) << hllSparsePrecision)
+	return m * math.Log(m/(m-float64(n)))
+}
+
+// mergeSparse сливает два отсортированных списка, оставляя для каждого
+// индекса наибольший rho. Элементы с одним индексом идут подряд
+// по возрастанию rho, поэтому достаточно оставлять последний
+func mergeSparse(a, b []uint32) []uint32 {
+	out := make([]uint32, 0, len(a)+len(b))
+	push := func(e uint32) {
+		if n := len(out); n > 0 && out[n-1]>>hllSparseRhoBits == e>>hllSparseRhoBits {
+			out[n-1] = max(out[n-1], e)
+			return
+		}
+		out = append(out, e)
+	}
+	for len(a) > 0 && len(b) > 0 {
+		if a[0] <= b[0] {
+			push(a[0])
+			a = a[1:]
+		} else {
+			push(b[0])
+			b = b[1:]
+		}
+	}
+	for _, e := range a {
+		push(e)
+	}
+	for _, e := range b {
+		push(e)
+	}
+	return out
+}
+
+// encodeSparse дописывает к dst разности отсортированных элементов в varint
+func encodeSparse(dst []byte, entries []uint32) []byte {
+	prev := uint32(0)
+	for _, e := range entries {
+		dst = binary.AppendUvarint(dst, uint64(e-prev))
+		prev = e
+	}
+	return dst
+}
+
+// decodeSparse восстанавливает элементы из разностей
+func decodeSparse(data []byte) []uint32 {
+	var entries []uint32
+	prev := uint32(0)
+	for len(data) > 0 {
+		d, k := binary.Uvarint(data)
+		data = data[k:]
+		prev += uint32(d)
+		entries = append(entries, prev)
+	}
+	return entries
+}
@@ -0,0 +1,148 @@
+package sketch
+
+import (
+	"bytes"
+	"flag"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestNewHyperLogLogPrecision(t *testing.T) {
+	for _, p := range []int{0, 3, 19} {
+		if _, err := NewHyperLogLog(p); err != ErrInvalidParams {
+			t.Errorf("NewHyperLogLog(%d): %v, want ErrInvalidParams", p, err)
+		}
+	}
+	hll, err := NewHyperLogLog(14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hll.Memory() != 0 || hll.Precision() != 14 {
+		t.Errorf("Memory = %d, Precision = %d", hll.Memory(), hll.Precision())
+	}
+	hll.toDense()
+	if hll.Memory() != 1<<14 {
+		t.Errorf("Memory = %d после перехода к регистрам, want %d", hll.Memory(), 1<<14)
+	}
+	if se := hll.StandardError(); math.Abs(se-0.008125) > 1e-9 {
+		t.Errorf("StandardError = %v, want 0.008125", se)
+	}
+}
+
+func TestHyperLogLogAccuracy(t *testing.T) {
+	const n = 200_000
+	for _, p := range []int{4, 8, 12, 16} {
+		hll, _ := NewHyperLogLog(p)
+		for i := 0; i < n; i++ {
+			hll.Add("element-" + strconv.Itoa(i))
+		}
+		// одна оценка почти всегда укладывается в 4 стандартные ошибки
+		if rel := math.Abs(hll.Estimate()-n) / n; rel > 4*hll.StandardError() {
+			t.Errorf("p = %d: относительная ошибка %.4f, стандартная %.4f", p, rel, hll.StandardError())
+		}
+	}
+}
+
+// hllFull включает долгий прогон TestHyperLogLogSyntheticHashes до 10^9:
+//
+//	go test ./sketch -run SyntheticHashes -hll.full
+var hllFull = flag.Bool("hll.full", false, "проверять HyperLogLog до 10^9 элементов")
+
+func TestHyperLogLogSyntheticHashes(t *testing.T) {
+	// один счётчик с p = 14 проходит кардинальности 1, 2, 5, 10, ... 10^7,
+	// а с флагом -hll.full — до 10^9; хэши — последовательность splitmix64,
+	// как у равномерного хэша
+	limit := 10_000_000
+	if *hllFull {
+		limit = 1_000_000_000
+	}
+	hll, _ := NewHyperLogLog(14)
+	seed := uint64(1)
+	n := 0
+	for decade := 1; decade <= limit; decade *= 10 {
+		for _, c := range []int{decade, 2 * decade, 5 * decade} {
+			if c > limit {
+				break
+			}
+			for ; n < c; n++ {
+				hll.AddHash(splitmix64(&seed))
+			}
+			got := hll.Estimate()
+			if math.Abs(got-float64(n)) > 4*hll.StandardError()*float64(n)+0.5 {
+				t.Errorf("n = %d: оценка %.0f, ошибка %.2f%%", n, got, (got/float64(n)-1)*100)
+			}
+		}
+	}
+}
+
+func TestHyperLogLogBiasCorrected(t *testing.T) {
+	// среднее по многим счётчикам в диапазоне 2m..5m, где сырая оценка
+	// HyperLogLog смещена, а линейный счёт уже не применяется. Число
+	// счётчиков подобрано так, что стандартная ошибка среднего около 0,1%
+	seed := uint64(7)
+	for _, p := range []int{6, 10, 14} {
+		m := 1 << p
+		trials := 1 << 20 / m
+		for _, f := range []int{2, 3, 4, 5} {
+			n := f * m
+			sum := 0.0
+			for i := 0; i < trials; i++ {
+				hll, _ := NewHyperLogLog(p)
+				for j := 0; j < n; j++ {
+					hll.AddHash(splitmix64(&seed))
+				}
+				sum += hll.Estimate()/float64(n) - 1
+			}
+			if bias := sum / float64(trials); math.Abs(bias) > 0.004 {
+				t.Errorf("p = %d, n = %dm: среднее смещение %.2f%%", p, f, bias*100)
+			}
+		}
+	}
+}
+
+func TestHyperLogLogSparseMatchesDense(t *testing.T) {
+	for _, p := range []int{4, 10, 14} {
+		sparse, _ := NewHyperLogLog(p)
+		dense, _ := NewHyperLogLog(p)
+		dense.toDense()
+		seed := uint64(p)
+		n := 0
+		for ; sparse.registers == nil; n++ {
+			h := splitmix64(&seed)
+			sparse.AddHash(h)
+			dense.AddHash(h)
+			if n < 1000 && n%37 == 0 {
+				// разреженная оценка — линейный счёт по 2^25 ячейкам, почти точный
+				got := sparse.Estimate()
+				if sparse.registers == nil && math.Abs(got-float64(n+1)) > 0.01*float64(n+1)+0.5 {
+					t.Fatalf("p = %d, n = %d: разреженная оценка %.2f", p, n+1, got)
+				}
+				if sparse.registers == nil && sparse.Memory() > 1<<p {
+					t.Fatalf("p = %d: разреженный счётчик занимает %d байт", p, sparse.Memory())
+				}
+			}
+		}
+		if !bytes.Equal(sparse.registers, dense.registers) {
+			t.Fatalf("p = %d: регистры после перехода отличаются от плотного счётчика", p)
+		}
+		t.Logf("p = %d: переход к регистрам после %d элементов", p, n)
+	}
+}
+
+func TestHyperLogLogSmallMemory(t *testing.T) {
+	// сотни тысяч счётчиков по несколько элементов не должны занимать
+	// по четверти плотного массива каждый
+	for _, p := range []int{4, 14, 18} {
+		hll, _ := NewHyperLogLog(p)
+		for i := 0; i < 3; i++ {
+			hll.Add("customer-" + strconv.Itoa(i))
+		}
+		if mem := hll.Memory(); mem > 32 {
+			t.Errorf("p = %d: 3 элемента занимают %d байт", p, mem)
+		}
+		if got := hll.Estimate(); math.Abs(got-3) > 0.01 {
+			t.Errorf("p = %d: оценка %v, want 3", p, got)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+//go:build linux
+
+package sketch
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// Формат файла отображаемого фильтра. В отличие от BLMF он изменяется
+// на месте, поэтому контрольной суммы нет, а слова лежат в порядке байт
+// машины, чтобы отображённый массив можно было читать как []uint64:
+//
+//	[0:4]   магическое число "BLMM"
+//	[4]     версия формата
+//	[5]     идентификатор схемы хэширования
+//	[6]     режим индексов
+//	[7]     зарезервировано (ноль)
+//	[8:12]  hashCount (little-endian)
+//	[12:20] size в битах (little-endian)
+//	[20:64] зарезервировано (нули)
+//	[64:..] bitSet, слова по 8 байт
+const (
+	mappedMagic         = "BLMM"
+	mappedFormatVersion = 1
+	mappedHeaderSize    = 64 // кратно 8, чтобы слова были выровнены
+)
+
+// ErrReadOnly — попытка изменить фильтр, открытый только для чтения
+var ErrReadOnly = errors.New("sketch: фильтр открыт только для чтения")
+
+// MappedBloomFilter — фильтр Блума, битовый массив которого лежит в файле,
+// отображённом в память. Массив не попадает в кучу, поэтому размер
+// ограничен диском и адресным пространством, а не сборщиком мусора:
+// миллиард элементов с ошибкой 1% занимает ~1.2 ГБ страничного кэша.
+//
+// Один процесс может заполнять фильтр, остальные — одновременно открыть
+// тот же файл только для чтения: отображение общее (MAP_SHARED), и
+// записанные биты сразу видны всем. Add из нескольких горутин одного
+// процесса не потокобезопасен
+type MappedBloomFilter struct {
+	bf       BloomFilter // bitSet указывает в data
+	file     *os.File
+	data     []byte
+	readOnly bool
+}
+
+// CreateMappedBloomFilter создаёт файл path с пустым фильтром из size бит
+// и hashCount хэш-функций. Существующий файл не перезаписывается: его
+// могут читать другие процессы
+func CreateMappedBloomFilter(path string, size, hashCount int) (*MappedBloomFilter, error) {
+	if size <= 0 || hashCount <= 0 {
+		return nil, ErrInvalidParams
+	}
+	if err := checkNativeLittleEndian(); err != nil {
+		return nil, err
+	}
+	scheme, _ := hashSchemeOf(DefaultHasher)
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	fileSize := int64(mappedHeaderSize + (size+63)/64*8)
+	// файл получается разреженным: место на диске выделяется по мере
+	// установки битов
+	if err := f.Truncate(fileSize); err != nil {
+		f.Close()
+		os.Remove(path)
+		return nil, err
+	}
+	mf, err := mapBloomFilter(f, fileSize, false)
+	if err != nil {
+		f.Close()
+		os.Remove(path)
+		return nil, err
+	}
+
+	header := mf.data[:mappedHeaderSize]
+	copy(header[0:4], mappedMagic)
+	header[4] = mappedFormatVersion
+	header[5] = scheme
+	header[6] = byte(indexDoubleHashing)
+	binary.LittleEndian.PutUint32(header[8:12], uint32(hashCount))
+	binary.LittleEndian.PutUint64(header[12:20], uint64(size))
+	if err := mf.parseHeader(); err != nil {
+		// файл создан этим вызовом, недописанный фильтр не оставляем
+		mf.Close()
+		os.Remove(path)
+		return nil, err
+	}
+	return mf, nil
+}
+
+// CreateMappedBloomFilterWithRate создаёт файловый фильтр на n элементов
+// с вероятностью ложного срабатывания не выше p (см. OptimalParams)
+func CreateMappedBloomFilterWithRate(path string, n int, p float64) (*MappedBloomFilter, error) {
+	size, hashCount, err := OptimalParams(n, p)
+	if err != nil {
+		return nil, err
+	}
+	return CreateMappedBloomFilter(path, size, hashCount)
+}
+
+// OpenMappedBloomFilter открывает существующий файл фильтра.
+// flag — os.O_RDONLY для проверки элементов или os.O_RDWR для добавления
+func OpenMappedBloomFilter(path string, flag int) (*MappedBloomFilter, error) {
+	if flag != os.O_RDONLY && flag != os.O_RDWR {
+		return nil, ErrInvalidParams
+	}
+	if err := checkNativeLittleEndian(); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, flag, 0)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if fi.Size() < mappedHeaderSize || fi.Size()%8 != 0 {
+		f.Close()
+		return nil, fmt.Errorf("%w: размер файла %d байт", ErrCorrupt, fi.Size())
+	}
+	mf, err := mapBloomFilter(f, fi.Size(), flag == os.O_RDONLY)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := mf.parseHeader(); err != nil {
+		mf.Close()
+		return nil, err
+	}
+	return mf, nil
+}
+
+// mapBloomFilter отображает файл целиком
+func mapBloomFilter(f *os.File, size int64, readOnly bool) (*MappedBloomFilter, error) {
+	prot := syscall.PROT_READ
+	if !readOnly {
+		prot |= syscall.PROT_WRITE
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), prot, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("sketch: mmap %s: %w", f.Name(), err)
+	}
+	// обращения к фильтру случайны, упреждающее чтение только мешает
+	syscall.Madvise(data, syscall.MADV_RANDOM)
+	return &MappedBloomFilter{file: f, data: data, readOnly: readOnly}, nil
+}
+
+// parseHeader проверяет заголовок и настраивает bf на отображённый массив
+func (mf *MappedBloomFilter) parseHeader() error {
+	header := mf.data[:mappedHeaderSize]
+	if string(header[0:4]) != mappedMagic {
+		return ErrBadMagic
+	}
+	if header[4] != mappedFormatVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedFormat, header[4])
+	}
+	hasher, ok := hasherOf(header[5])
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrUnknownHashScheme, header[5])
+	}
+	mode := indexMode(header[6])
+	hashCount := binary.LittleEndian.Uint32(header[8:12])
+	size := binary.LittleEndian.Uint64(header[12:20])
+	words := (size + 63) / 64
+	if mode > indexGuava64 || header[7] != 0 || hashCount == 0 || size == 0 ||
+		uint64(len(mf.data)-mappedHeaderSize)/8 != words {
+		return ErrCorrupt
+	}
+
+	mf.bf = BloomFilter{
+		bitSet:    unsafe.Slice((*uint64)(unsafe.Pointer(&mf.data[mappedHeaderSize])), words),
+		size:      int(size),
+		hashCount: int(hashCount),
+		hasher:    hasher,
+		mode:      mode,
+	}
+	return nil
+}
+
+// checkNativeLittleEndian отказывает на big-endian машинах: слова
+// в файле читаются напрямую и должны совпадать с little-endian заголовком
+func checkNativeLittleEndian() error {
+	if binary.NativeEndian.Uint16([]byte{1, 0}) != 1 {
+		return fmt.Errorf("%w: отображаемый фильтр поддерживается только на little-endian", ErrUnsupportedFormat)
+	}
+	return nil
+}
+
+// Add добавляет элемент. Для фильтра, открытого только для чтения,
+// возвращает ErrReadOnly
+func (mf *MappedBloomFilter) Add(item []byte) error {
+	if mf.readOnly {
+		return ErrReadOnly
+	}
+	mf.bf.Add(item)
+	return nil
+}
+
+func (mf *MappedBloomFilter) Nalich(item []byte) bool {
+	return mf.bf.Nalich(item)
+}
+
+// Size возвращает размер битового массива в битах (m)
+func (mf *MappedBloomFilter) Size() int {
+	return mf.bf.Size()
+}
+
+// HashCount возвращает количество хэш-функций (k)
+func (mf *MappedBloomFilter) HashCount() int {
+	return mf.bf.HashCount()
+}
+
+// FalsePositiveRate возвращает теоретическую вероятность ложного
+// срабатывания после добавления n различных элементов
+func (mf *MappedBloomFilter) FalsePositiveRate(n int) float64 {
+	return mf.bf.FalsePositiveRate(n)
+}
+
+// Memory возвращает размер битового массива в байтах. Эта память
+// принадлежит страничному кэшу, а не куче Go
+func (mf *MappedBloomFilter) Memory() int {
+	return mf.bf.Memory()
+}
+
+// Flush синхронно сбрасывает изменённые страницы на диск (msync).
+// Другим процессам изменения видны и без него; Flush нужен, чтобы
+// они пережили сбой машины
+func (mf *MappedBloomFilter) Flush() error {
+	if mf.readOnly {
+		return nil
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_MSYNC,
+		uintptr(unsafe.Pointer(&mf.data[0])), uintptr(len(mf.data)), syscall.MS_SYNC)
+	if errno != 0 {
+		return fmt.Errorf("sketch: msync %s: %w", mf.file.Name(), errno)
+	}
+	return nil
+}
+
+// Close сбрасывает изменения на диск, снимает отображение и закрывает файл.
+// После Close фильтром пользоваться нельзя
+func (mf *MappedBloomFilter) Close() error {
+	if mf.data == nil {
+		return nil
+	}
+	err := mf.Flush()
+	mf.bf = BloomFilter{}
+	if e := syscall.Munmap(mf.data); err == nil {
+		err = e
+	}
+	mf.data = nil
+	if e := mf.file.Close(); err == nil {
+		err = e
+	}
+	return err
+}
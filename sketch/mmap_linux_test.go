@@ -0,0 +1,101 @@
+//go:build linux
+
+package sketch
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestMappedBloomFilter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "filter.blmm")
+	const n = 10_000
+
+	mf, err := CreateMappedBloomFilterWithRate(path, n, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CreateMappedBloomFilter(path, 1024, 3); !errors.Is(err, os.ErrExist) {
+		t.Errorf("повторный Create: %v, want os.ErrExist", err)
+	}
+	// эталон в куче с теми же параметрами должен дать те же биты
+	ref := NewBloomFilter(mf.Size(), mf.HashCount())
+	for i := 0; i < n; i++ {
+		item := []byte(fmt.Sprintf("element-%d", i))
+		if err := mf.Add(item); err != nil {
+			t.Fatal(err)
+		}
+		ref.Add(item)
+	}
+
+	// читатель видит биты писателя до Flush и Close
+	reader, err := OpenMappedBloomFilter(path, os.O_RDONLY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	if err := mf.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if reader.Size() != ref.Size() || reader.HashCount() != ref.HashCount() {
+		t.Fatalf("size, k = %d, %d, want %d, %d", reader.Size(), reader.HashCount(), ref.Size(), ref.HashCount())
+	}
+	for i := range ref.bitSet {
+		if reader.bf.bitSet[i] != ref.bitSet[i] {
+			t.Fatalf("слово %d = %#x, want %#x", i, reader.bf.bitSet[i], ref.bitSet[i])
+		}
+	}
+	for i := 0; i < n; i++ {
+		if !reader.Nalich([]byte(fmt.Sprintf("element-%d", i))) {
+			t.Fatalf("element-%d не найден", i)
+		}
+	}
+	if err := reader.Add([]byte("x")); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Add в режиме чтения: %v, want ErrReadOnly", err)
+	}
+}
+
+func TestOpenMappedBloomFilterErrors(t *testing.T) {
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad")
+	if err := os.WriteFile(bad, make([]byte, 128), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenMappedBloomFilter(bad, os.O_RDONLY); !errors.Is(err, ErrBadMagic) {
+		t.Errorf("файл без заголовка: %v, want ErrBadMagic", err)
+	}
+
+	short := filepath.Join(dir, "short")
+	mf, err := CreateMappedBloomFilter(short, 1024, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mf.Close()
+	if err := os.Truncate(short, mappedHeaderSize+64); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenMappedBloomFilter(short, os.O_RDWR); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("обрезанный файл: %v, want ErrCorrupt", err)
+	}
+}
+
+func TestCreateMappedBloomFilterRemovesFile(t *testing.T) {
+	if strconv.IntSize < 64 {
+		t.Skip("нужен 64-битный int")
+	}
+	// 2^32 хэш-функций не помещаются в заголовок, и он не проходит проверку
+	hashCount := 1
+	hashCount <<= 32
+	path := filepath.Join(t.TempDir(), "filter.blmm")
+	if _, err := CreateMappedBloomFilter(path, 1024, hashCount); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("CreateMappedBloomFilter: %v, want ErrCorrupt", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("после ошибки файл остался: %v", err)
+	}
+}
@@ -0,0 +1,246 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// Split block Bloom filter из спецификации Parquet
+// (https://github.com/apache/parquet-format/blob/master/BloomFilter.md).
+// Фильтр — массив 256-битных блоков из восьми uint32. Значение хэшируется
+// xxHash64 с seed 0, старшие 32 бита хэша выбирают блок, младшие 32 бита,
+// умноженные на восемь «солей», дают по одному биту в каждом слове блока
+const (
+	sbbfBlockBytes = 32
+	sbbfMinBytes   = sbbfBlockBytes
+	sbbfMaxBytes   = 128 << 20 // предел parquet-mr
+)
+
+var sbbfSalt = [8]uint32{
+	0x47b6137b, 0x44974d91, 0x8824ad5b, 0xa2b7289d,
+	0x705495c7, 0x2df1424b, 0x9efc4947, 0x5c6bfb31,
+}
+
+// ErrParquetHeader — заголовок BloomFilterHeader не разобран или описывает
+// неподдерживаемый алгоритм, хэш или сжатие
+var ErrParquetHeader = errors.New("sketch: некорректный заголовок фильтра Parquet")
+
+// SplitBlockBloomFilter — фильтр Блума колонок Parquet, побайтно совместимый
+// с parquet-mr, arrow и другими реализациями
+type SplitBlockBloomFilter struct {
+	blocks [][8]uint32
+}
+
+// NewSplitBlockBloomFilter создаёт фильтр размером numBytes байт.
+// Размер должен быть степенью двойки от 32 байт до 128 МБ
+func NewSplitBlockBloomFilter(numBytes int) (*SplitBlockBloomFilter, error) {
+	if numBytes < sbbfMinBytes || numBytes > sbbfMaxBytes || numBytes&(numBytes-1) != 0 {
+		return nil, ErrInvalidParams
+	}
+	return &SplitBlockBloomFilter{blocks: make([][8]uint32, numBytes/sbbfBlockBytes)}, nil
+}
+
+// NewSplitBlockBloomFilterWithRate подбирает размер для ndv различных значений
+// и ошибки p по формуле из спецификации:
+//
+//	m = -8 * ndv / ln(1 - p^(1/8))
+//
+// с округлением вверх до степени двойки
+func NewSplitBlockBloomFilterWithRate(ndv int, p float64) (*SplitBlockBloomFilter, error) {
+	if ndv <= 0 || !(p > 0 && p < 1) {
+		return nil, ErrInvalidParams
+	}
+	numBits := -8 * float64(ndv) / math.Log(1-math.Pow(p, 1.0/8))
+	numBytes := uint64(math.Ceil(numBits / 8))
+	numBytes = max(numBytes, sbbfMinBytes)
+	numBytes = 1 << bits.Len64(numBytes-1)
+	if numBytes > sbbfMaxBytes {
+		numBytes = sbbfMaxBytes
+	}
+	return NewSplitBlockBloomFilter(int(numBytes))
+}
+
+// ParquetHash хэширует значение так, как это делает Parquet: xxHash64 с seed 0
+// от значения в plain-кодировке. Для BYTE_ARRAY и FIXED_LEN_BYTE_ARRAY это
+// сами байты без длины, для INT32/INT64/FLOAT/DOUBLE — little-endian байты
+func ParquetHash(value []byte) uint64 {
+	return xxhash64(value, 0)
+}
+
+// sbbfMask возвращает по одному биту для каждого из восьми слов блока
+func sbbfMask(x uint32) [8]uint32 {
+	var mask [8]uint32
+	for i, s := range sbbfSalt {
+		mask[i] = 1 << ((x * s) >> 27)
+	}
+	return mask
+}
+
+// block выбирает блок по старшим 32 битам хэша
+func (f *SplitBlockBloomFilter) block(hash uint64) *[8]uint32 {
+	return &f.blocks[(hash>>32)*uint64(len(f.blocks))>>32]
+}
+
+// InsertHash добавляет уже посчитанный хэш значения
+func (f *SplitBlockBloomFilter) InsertHash(hash uint64) {
+	b := f.block(hash)
+	for i, m := range sbbfMask(uint32(hash)) {
+		b[i] |= m
+	}
+}
+
+// CheckHash проверяет уже посчитанный хэш значения
+func (f *SplitBlockBloomFilter) CheckHash(hash uint64) bool {
+	b := f.block(hash)
+	for i, m := range sbbfMask(uint32(hash)) {
+		if b[i]&m == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Insert добавляет значение в plain-кодировке (см. ParquetHash)
+func (f *SplitBlockBloomFilter) Insert(value []byte) {
+	f.InsertHash(ParquetHash(value))
+}
+
+// Check проверяет значение в plain-кодировке (см. ParquetHash)
+func (f *SplitBlockBloomFilter) Check(value []byte) bool {
+	return f.CheckHash(ParquetHash(value))
+}
+
+// Bitset возвращает битовый массив в том виде, в каком он лежит
+// в файле Parquet после заголовка: блоки подряд, слова little-endian
+func (f *SplitBlockBloomFilter) Bitset() []byte {
+	out := make([]byte, 0, len(f.blocks)*sbbfBlockBytes)
+	for _, b := range f.blocks {
+		for _, w := range b {
+			out = binary.LittleEndian.AppendUint32(out, w)
+		}
+	}
+	return out
+}
+
+// NewSplitBlockBloomFilterFromBitset восстанавливает фильтр по битовому
+// массиву из файла Parquet
+func NewSplitBlockBloomFilterFromBitset(bitset []byte) (*SplitBlockBloomFilter, error) {
+	f, err := NewSplitBlockBloomFilter(len(bitset))
+	if err != nil {
+		return nil, err
+	}
+	for i := range f.blocks {
+		for j := range f.blocks[i] {
+			f.blocks[i][j] = binary.LittleEndian.Uint32(bitset[i*sbbfBlockBytes+j*4:])
+		}
+	}
+	return f, nil
+}
+
+// Memory возвращает размер битового массива в байтах
+func (f *SplitBlockBloomFilter) Memory() int {
+	return len(f.blocks) * sbbfBlockBytes
+}
+
+// MarshalBinary возвращает фильтр так, как он хранится в файле Parquet:
+// BloomFilterHeader в Thrift Compact Protocol и за ним битовый массив
+func (f *SplitBlockBloomFilter) MarshalBinary() ([]byte, error) {
+	return append(parquetHeader(f.Memory()), f.Bitset()...), nil
+}
+
+// UnmarshalBinary разбирает заголовок BloomFilterHeader и битовый массив
+func (f *SplitBlockBloomFilter) UnmarshalBinary(data []byte) error {
+	numBytes, n, err := parseParquetHeader(data)
+	if err != nil {
+		return err
+	}
+	if len(data)-n != numBytes {
+		return fmt.Errorf("%w: в заголовке %d байт, в данных %d", ErrCorrupt, numBytes, len(data)-n)
+	}
+	g, err := NewSplitBlockBloomFilterFromBitset(data[n:])
+	if err != nil {
+		return err
+	}
+	*f = *g
+	return nil
+}
+
+// Заголовок в Thrift:
+//
+//	struct BloomFilterHeader {
+//	  1: required i32 numBytes;
+//	  2: required BloomFilterAlgorithm algorithm;     // union { 1: SplitBlockAlgorithm BLOCK }
+//	  3: required BloomFilterHash hash;               // union { 1: XxHash XXHASH }
+//	  4: required BloomFilterCompression compression; // union { 1: Uncompressed UNCOMPRESSED }
+//	}
+//
+// В Compact Protocol заголовок поля — байт (приращение id << 4) | тип
+const (
+	thriftStop   = 0
+	thriftI32    = 5
+	thriftStruct = 12
+)
+
+// parquetHeader кодирует BloomFilterHeader для SBBF, xxHash и без сжатия
+func parquetHeader(numBytes int) []byte {
+	// i32 записывается как varint в zigzag-кодировке
+	v := int32(numBytes)
+	out := []byte{1<<4 | thriftI32}
+	out = binary.AppendUvarint(out, uint64(uint32(v<<1^v>>31)))
+	for id := 2; id <= 4; id++ {
+		// поле-объединение, в нём пустая структура с id 1
+		out = append(out, 1<<4|thriftStruct, 1<<4|thriftStruct, thriftStop, thriftStop)
+	}
+	return append(out, thriftStop)
+}
+
+// parseParquetHeader разбирает BloomFilterHeader и возвращает numBytes
+// и длину заголовка. Другие алгоритмы, хэши и сжатие спецификация пока
+// не определяет, поэтому любые другие поля считаются ошибкой
+func parseParquetHeader(data []byte) (numBytes, n int, err error) {
+	var seen [5]bool
+	id := 0
+	for {
+		if n >= len(data) {
+			return 0, 0, fmt.Errorf("%w: заголовок обрезан", ErrParquetHeader)
+		}
+		h := data[n]
+		n++
+		if h == thriftStop {
+			break
+		}
+		if h>>4 == 0 {
+			return 0, 0, fmt.Errorf("%w: длинная форма заголовка поля не поддерживается", ErrParquetHeader)
+		}
+		id += int(h >> 4)
+		switch typ := h & 0x0f; {
+		case id == 1 && typ == thriftI32:
+			v, k := binary.Uvarint(data[n:])
+			if k <= 0 {
+				return 0, 0, fmt.Errorf("%w: numBytes", ErrParquetHeader)
+			}
+			n += k
+			numBytes = int(int32(uint32(v)>>1) ^ -int32(v&1))
+		case id >= 2 && id <= 4 && typ == thriftStruct:
+			// в объединении должен быть выбран вариант 1 (BLOCK, XXHASH, UNCOMPRESSED)
+			if len(data) < n+3 || data[n] != 1<<4|thriftStruct || data[n+1] != thriftStop || data[n+2] != thriftStop {
+				return 0, 0, fmt.Errorf("%w: поле %d: поддерживаются только SPLIT_BLOCK, XXHASH и UNCOMPRESSED", ErrParquetHeader, id)
+			}
+			n += 3
+		default:
+			return 0, 0, fmt.Errorf("%w: неожиданное поле %d типа %d", ErrParquetHeader, id, typ)
+		}
+		if id < len(seen) {
+			seen[id] = true
+		}
+	}
+	for id := 1; id <= 4; id++ {
+		if !seen[id] {
+			return 0, 0, fmt.Errorf("%w: нет обязательного поля %d", ErrParquetHeader, id)
+		}
+	}
+	return numBytes, n, nil
+}
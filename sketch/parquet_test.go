@@ -0,0 +1,85 @@
+package sketch
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// Эталонные байты посчитаны по псевдокоду спецификации Parquet независимо
+// от этой реализации. xxHash64("abc") = 0x44bc2cf5ad770999,
+// xxHash64("") = 0xef46db3751d8e999
+func TestSplitBlockBloomFilterGolden(t *testing.T) {
+	tests := []struct {
+		numBytes int
+		values   []string
+		want     string
+	}{
+		{32, nil, "0000000000000000000000000000000000000000000000000000000000000000"},
+		{32, []string{"abc"}, "0020000000080000000080000000200040000000004000000000002000000020"},
+		{64, []string{"abc", ""}, "0020000000080000000080000000200040000000004000000000002000000020" +
+			"0000002001000000000000020000001000400000000040000000002000000040"},
+	}
+	for _, tt := range tests {
+		f, err := NewSplitBlockBloomFilter(tt.numBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range tt.values {
+			f.Insert([]byte(v))
+		}
+		if got := hex.EncodeToString(f.Bitset()); got != tt.want {
+			t.Errorf("%q: bitset = %s, want %s", tt.values, got, tt.want)
+		}
+		for _, v := range tt.values {
+			if !f.Check([]byte(v)) {
+				t.Errorf("%q не найден", v)
+			}
+		}
+	}
+}
+
+func TestSplitBlockBloomFilterHeaderGolden(t *testing.T) {
+	f, _ := NewSplitBlockBloomFilter(1024)
+	f.Insert([]byte("abc"))
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// numBytes = 1024 (zigzag varint 80 10), затем BLOCK, XXHASH, UNCOMPRESSED
+	header, _ := hex.DecodeString("15801" + "01c1c00001c1c00001c1c000000")
+	if !bytes.Equal(data[:len(header)], header) {
+		t.Fatalf("заголовок = %x, want %x", data[:len(header)], header)
+	}
+	if !bytes.Equal(data[len(header):], f.Bitset()) {
+		t.Fatal("после заголовка должен идти битовый массив")
+	}
+
+	var g SplitBlockBloomFilter
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !g.Check([]byte("abc")) || !bytes.Equal(g.Bitset(), f.Bitset()) {
+		t.Fatal("фильтр после UnmarshalBinary отличается")
+	}
+
+	// алгоритм, отличный от BLOCK (вариант 2 объединения)
+	bad := bytes.Clone(data)
+	bad[4] = 2<<4 | thriftStruct
+	if err := g.UnmarshalBinary(bad); err == nil {
+		t.Error("неизвестный алгоритм должен отклоняться")
+	}
+}
+
+func TestSplitBlockBloomFilterFalsePositiveRate(t *testing.T) {
+	const n, probes = 100_000, 200_000
+	f, err := NewSplitBlockBloomFilterWithRate(n, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := measureFPR(f.Insert, f.Check, n, probes)
+	// размер округлён до степени двойки, поэтому ошибка не выше заданной
+	if got > 0.01 {
+		t.Errorf("FPR = %.5f, ожидалось не больше 0.01", got)
+	}
+}
@@ -0,0 +1,416 @@
+package sketch
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// Служебные биты ячейки фильтра с частным. Сама ячейка хранит
+// остаток, сдвинутый на три бита влево
+const (
+	qfOccupied     = 1 << iota // в канонической ячейке i есть хотя бы один элемент с частным i
+	qfContinuation             // ячейка продолжает серию предыдущей
+	qfShifted                  // остаток лежит не в своей канонической ячейке
+	qfMetaBits     = 3
+	qfMetaMask     = 1<<qfMetaBits - 1
+
+	qfMaxLoad = 0.75 // заполнение, на которое рассчитывается размер
+)
+
+// ErrQuotientFull — в фильтре с частным не осталось свободных ячеек.
+// Фильтр можно увеличить методом Resize
+var ErrQuotientFull = errors.New("sketch: фильтр с частным заполнен")
+
+// ErrQuotientIncompatible — фильтры с разной длиной частного или остатка
+// либо с разными хэш-функциями нельзя объединить: один и тот же элемент
+// даёт в них разные отпечатки
+var ErrQuotientIncompatible = errors.New("sketch: фильтры с частным несовместимы")
+
+// QuotientFilter — фильтр с частным (Bender и др., 2012). Отпечаток
+// элемента из q+r бит делится на частное (старшие q бит) — номер
+// канонической ячейки в таблице из 2^q ячеек — и остаток (младшие r бит),
+// который хранится в самой таблице. Остатки с одинаковым частным лежат
+// подряд (серия), серии идут в порядке частных и при коллизиях сдвигаются
+// вправо; три служебных бита в каждой ячейке позволяют восстановить,
+// какому частному принадлежит остаток.
+//
+// Так как таблица хранит отпечатки целиком, фильтр поддерживает удаление,
+// слияние двух фильтров одним проходом по отсортированным отпечаткам и
+// удвоение размера без повторного хэширования ключей: один бит остатка
+// переходит в частное. Повторно добавленный элемент хранится дважды
+type QuotientFilter struct {
+	data  []uint64 // ячейки по rbits+3 бит подряд
+	qbits uint
+	rbits uint
+	size  uint64 // 2^qbits
+	count int
+
+	hasher Hasher
+}
+
+// qfEntry — элемент кластера: частное относительно начала кластера и остаток
+type qfEntry struct {
+	rq, rem uint64
+}
+
+// NewQuotientFilter создаёт фильтр из 2^qbits ячеек с остатками по rbits бит.
+// Ошибка при n элементах примерно равна n / 2^(qbits+rbits)
+func NewQuotientFilter(qbits, rbits int) (*QuotientFilter, error) {
+	if qbits < 1 || qbits > 40 || rbits < 1 || qbits+rbits > 64 {
+		return nil, ErrInvalidParams
+	}
+	size := uint64(1) << qbits
+	width := uint64(rbits + qfMetaBits)
+	return &QuotientFilter{
+		data:   make([]uint64, (size*width+63)/64),
+		qbits:  uint(qbits),
+		rbits:  uint(rbits),
+		size:   size,
+		hasher: DefaultHasher,
+	}, nil
+}
+
+// NewQuotientFilterWithRate подбирает таблицу на n элементов при заполнении
+// не выше 75% и остаток так, чтобы ошибка при n элементах не превышала p
+func NewQuotientFilterWithRate(n int, p float64) (*QuotientFilter, error) {
+	if n <= 0 || !(p > 0 && p < 1) {
+		return nil, ErrInvalidParams
+	}
+	qbits := bits.Len64(uint64(math.Ceil(float64(n)/qfMaxLoad)) - 1)
+	qbits = max(qbits, 1)
+	// n / 2^(q+r) <= p; два бита остатка — минимум, чтобы можно было сделать Resize
+	rbits := int(math.Ceil(math.Log2(float64(n) / p)))
+	rbits = max(rbits-qbits, 2)
+	return NewQuotientFilter(qbits, rbits)
+}
+
+// SetHasher задаёт хэш-функцию. Вызывать до добавления элементов
+func (qf *QuotientFilter) SetHasher(h Hasher) {
+	qf.hasher = h
+}
+
+// fingerprint возвращает отпечаток из qbits+rbits старших бит хэша
+func (qf *QuotientFilter) fingerprint(item []byte) uint64 {
+	v1, _ := qf.hasher.Sum128(item)
+	return v1 >> (64 - qf.qbits - qf.rbits)
+}
+
+// slot возвращает ячейку i целиком: служебные биты и остаток
+func (qf *QuotientFilter) slot(i uint64) uint64 {
+	width := uint64(qf.rbits + qfMetaBits)
+	bit := i * width
+	w, off := bit/64, bit%64
+	v := qf.data[w] >> off
+	if off+width > 64 {
+		v |= qf.data[w+1] << (64 - off)
+	}
+	return v & (1<<width - 1)
+}
+
+// setSlot записывает ячейку i целиком
+func (qf *QuotientFilter) setSlot(i, v uint64) {
+	width := uint64(qf.rbits + qfMetaBits)
+	mask := uint64(1)<<width - 1
+	bit := i * width
+	w, off := bit/64, bit%64
+	qf.data[w] = qf.data[w]&^(mask<<off) | v<<off
+	if off+width > 64 {
+		qf.data[w+1] = qf.data[w+1]&^(mask>>(64-off)) | v>>(64-off)
+	}
+}
+
+// clusterStart возвращает начало кластера, в который попадает ячейка i:
+// первую ячейку слева, остаток в которой не сдвинут
+func (qf *QuotientFilter) clusterStart(i uint64) uint64 {
+	for qf.slot(i)&qfShifted != 0 {
+		i = (i - 1) & (qf.size - 1)
+	}
+	return i
+}
+
+// decode читает ячейки от начала кластера s до первой пустой и
+// возвращает элементы в порядке хранения (по частному, затем по остатку)
+func (qf *QuotientFilter) decode(s uint64) []qfEntry {
+	var entries []qfEntry
+	var occupied []uint64 // частные встреченных серий, по порядку
+	next, cur := 0, uint64(0)
+	for j := uint64(0); j < qf.size; j++ {
+		v := qf.slot((s + j) & (qf.size - 1))
+		if v&qfMetaMask == 0 {
+			break
+		}
+		if v&qfOccupied != 0 {
+			occupied = append(occupied, j)
+		}
+		if v&qfContinuation == 0 {
+			// новая серия принадлежит следующему по порядку частному
+			cur = occupied[next]
+			next++
+		}
+		entries = append(entries, qfEntry{rq: cur, rem: v >> qfMetaBits})
+	}
+	return entries
+}
+
+// encode записывает элементы кластера начиная с ячейки s. span — сколько
+// ячеек кластер занимал до изменения, все они перезаписываются
+func (qf *QuotientFilter) encode(s uint64, entries []qfEntry, span int) {
+	vals := make([]uint64, max(span, len(entries)))
+	pos := uint64(0)
+	for i, e := range entries {
+		first := i == 0 || entries[i-1].rq != e.rq
+		if first {
+			pos = max(pos, e.rq)
+			vals[e.rq] |= qfOccupied
+		}
+		v := e.rem << qfMetaBits
+		if !first {
+			v |= qfContinuation
+		}
+		if pos != e.rq {
+			v |= qfShifted
+		}
+		vals[pos] |= v
+		pos++
+	}
+	for j, v := range vals {
+		qf.setSlot((s+uint64(j))&(qf.size-1), v)
+	}
+}
+
+func compareQFEntry(a, b qfEntry) int {
+	if a.rq != b.rq {
+		return cmpUint64(a.rq, b.rq)
+	}
+	return cmpUint64(a.rem, b.rem)
+}
+
+func cmpUint64(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// insertFingerprint добавляет отпечаток, перестраивая его кластер
+func (qf *QuotientFilter) insertFingerprint(f uint64) error {
+	// одна ячейка всегда остаётся пустой, иначе кластер не кончится
+	if uint64(qf.count)+2 > qf.size {
+		return ErrQuotientFull
+	}
+	q, rem := f>>qf.rbits, f&(1<<qf.rbits-1)
+	s := qf.clusterStart(q)
+	entries := qf.decode(s)
+	span := len(entries)
+	e := qfEntry{rq: (q - s) & (qf.size - 1), rem: rem}
+	i, _ := slices.BinarySearchFunc(entries, e, compareQFEntry)
+	entries = slices.Insert(entries, i, e)
+	qf.encode(s, entries, span+1)
+	qf.count++
+	return nil
+}
+
+// Insert добавляет элемент. Если свободных ячеек не осталось,
+// возвращает ErrQuotientFull и не меняет фильтр
+func (qf *QuotientFilter) Insert(item []byte) error {
+	return qf.insertFingerprint(qf.fingerprint(item))
+}
+
+// Contains проверяет элемент, не выделяя памяти: находит начало серии
+// частного, отсчитывая серии от начала кластера
+func (qf *QuotientFilter) Contains(item []byte) bool {
+	f := qf.fingerprint(item)
+	q, rem := f>>qf.rbits, f&(1<<qf.rbits-1)
+	if qf.slot(q)&qfOccupied == 0 {
+		return false
+	}
+	mask := qf.size - 1
+	b := qf.clusterStart(q)
+	s := b
+	// b идёт по занятым каноническим ячейкам, s — по началам их серий
+	for b != q {
+		for s = (s + 1) & mask; qf.slot(s)&qfContinuation != 0; s = (s + 1) & mask {
+		}
+		for b = (b + 1) & mask; qf.slot(b)&qfOccupied == 0; b = (b + 1) & mask {
+		}
+	}
+	for {
+		v := qf.slot(s)
+		if r := v >> qfMetaBits; r == rem {
+			return true
+		} else if r > rem {
+			return false // остатки в серии отсортированы
+		}
+		s = (s + 1) & mask
+		if qf.slot(s)&qfContinuation == 0 {
+			return false
+		}
+	}
+}
+
+// Delete удаляет одну копию элемента и возвращает false, если его не было.
+// Удалять можно только добавленные элементы: удаление ложного
+// срабатывания сотрёт отпечаток другого элемента
+func (qf *QuotientFilter) Delete(item []byte) bool {
+	f := qf.fingerprint(item)
+	q, rem := f>>qf.rbits, f&(1<<qf.rbits-1)
+	if qf.slot(q)&qfOccupied == 0 {
+		return false
+	}
+	s := qf.clusterStart(q)
+	entries := qf.decode(s)
+	i, found := slices.BinarySearchFunc(entries, qfEntry{rq: (q - s) & (qf.size - 1), rem: rem}, compareQFEntry)
+	if !found {
+		return false
+	}
+	span := len(entries)
+	qf.encode(s, slices.Delete(entries, i, i+1), span)
+	qf.count--
+	return true
+}
+
+// fingerprints возвращает все отпечатки по возрастанию
+func (qf *QuotientFilter) fingerprints() []uint64 {
+	out := make([]uint64, 0, qf.count)
+	if qf.count == 0 {
+		return out
+	}
+	mask := qf.size - 1
+	// обходим таблицу по кругу от пустой ячейки: так ни один кластер
+	// не окажется разрезан границей массива
+	e := uint64(0)
+	for qf.slot(e)&qfMetaMask != 0 {
+		e++
+	}
+	for j := uint64(1); j <= qf.size; j++ {
+		s := (e + j) & mask
+		if qf.slot(s)&qfMetaMask == 0 {
+			continue
+		}
+		entries := qf.decode(s)
+		for _, en := range entries {
+			out = append(out, ((s+en.rq)&mask)<<qf.rbits|en.rem)
+		}
+		j += uint64(len(entries))
+	}
+	// частные росли от e+1 и один раз перешли через ноль
+	for i := 1; i < len(out); i++ {
+		if out[i] < out[i-1] {
+			return append(out[i:], out[:i]...)
+		}
+	}
+	return out
+}
+
+// fill заполняет пустую таблицу отсортированными отпечатками за один
+// проход. Хвост, который не помещается до конца массива и должен
+// перейти через ноль, добавляется обычной вставкой
+func (qf *QuotientFilter) fill(fps []uint64) error {
+	pos := uint64(0)
+	i := 0
+	for ; i < len(fps); i++ {
+		q, rem := fps[i]>>qf.rbits, fps[i]&(1<<qf.rbits-1)
+		first := i == 0 || fps[i-1]>>qf.rbits != q
+		if first {
+			pos = max(pos, q)
+		}
+		if pos >= qf.size {
+			break
+		}
+		if first {
+			qf.setSlot(q, qf.slot(q)|qfOccupied)
+		}
+		v := rem << qfMetaBits
+		if !first {
+			v |= qfContinuation
+		}
+		if pos != q {
+			v |= qfShifted
+		}
+		qf.setSlot(pos, qf.slot(pos)&qfOccupied|v)
+		pos++
+		qf.count++
+	}
+	for ; i < len(fps); i++ {
+		if err := qf.insertFingerprint(fps[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Merge добавляет в qf все элементы other. Фильтры должны иметь
+// одинаковые размеры и хэш-функцию, иначе возвращается
+// ErrQuotientIncompatible. Отпечатки обоих фильтров сливаются
+// одним проходом, как в сортировке слиянием. Если суммарно элементы
+// не помещаются, возвращает ErrQuotientFull и не меняет фильтр
+func (qf *QuotientFilter) Merge(other *QuotientFilter) error {
+	if qf.qbits != other.qbits || qf.rbits != other.rbits || !sameHasher(qf.hasher, other.hasher) {
+		return ErrQuotientIncompatible
+	}
+	if uint64(qf.count+other.count)+1 > qf.size {
+		return ErrQuotientFull
+	}
+	a, b := qf.fingerprints(), other.fingerprints()
+	merged := make([]uint64, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		if a[0] <= b[0] {
+			merged, a = append(merged, a[0]), a[1:]
+		} else {
+			merged, b = append(merged, b[0]), b[1:]
+		}
+	}
+	merged = append(append(merged, a...), b...)
+
+	clear(qf.data)
+	qf.count = 0
+	return qf.fill(merged)
+}
+
+// Resize удваивает число ячеек, перенося старший бит остатка в частное.
+// Ключи повторно не хэшируются; длина отпечатка не меняется, поэтому
+// ошибка при том же числе элементов остаётся прежней. Требует остатка
+// не короче двух бит
+func (qf *QuotientFilter) Resize() error {
+	if qf.rbits < 2 || qf.qbits >= 40 {
+		return ErrInvalidParams
+	}
+	fps := qf.fingerprints()
+	bigger, err := NewQuotientFilter(int(qf.qbits+1), int(qf.rbits-1))
+	if err != nil {
+		return err
+	}
+	bigger.hasher = qf.hasher
+	// отпечатки те же числа: q+r бит, просто разбитые на 1 бит правее
+	if err := bigger.fill(fps); err != nil {
+		return err
+	}
+	*qf = *bigger
+	return nil
+}
+
+// Count возвращает количество хранимых отпечатков
+func (qf *QuotientFilter) Count() int {
+	return qf.count
+}
+
+// LoadFactor возвращает долю занятых ячеек
+func (qf *QuotientFilter) LoadFactor() float64 {
+	return float64(qf.count) / float64(qf.size)
+}
+
+// FalsePositiveRate возвращает теоретическую вероятность ложного
+// срабатывания при текущем числе элементов: 1 - e^(-n / 2^(q+r))
+func (qf *QuotientFilter) FalsePositiveRate() float64 {
+	return -math.Expm1(-float64(qf.count) / math.Ldexp(1, int(qf.qbits+qf.rbits)))
+}
+
+// Memory возвращает размер таблицы в байтах
+func (qf *QuotientFilter) Memory() int {
+	return len(qf.data) * 8
+}
@@ -0,0 +1,170 @@
+package sketch
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// checkQuotientFilter сравнивает содержимое фильтра с эталонным мультимножеством отпечатков
+func checkQuotientFilter(t *testing.T, qf *QuotientFilter, want map[uint64]int) {
+	t.Helper()
+	var fps []uint64
+	for f, c := range want {
+		for i := 0; i < c; i++ {
+			fps = append(fps, f)
+		}
+	}
+	slices.Sort(fps)
+	got := qf.fingerprints()
+	if !slices.Equal(got, fps) || qf.Count() != len(fps) {
+		t.Fatalf("отпечатки: %d шт., Count %d, want %d", len(got), qf.Count(), len(fps))
+	}
+}
+
+func TestQuotientFilterRandomOps(t *testing.T) {
+	// маленькая таблица, чтобы кластеры часто переходили через конец массива
+	qf, err := NewQuotientFilter(6, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rnd := rand.New(rand.NewPCG(1, 2))
+	want := map[uint64]int{}
+	var added [][]byte
+	for step := 0; step < 20_000; step++ {
+		if len(added) > 0 && (qf.Count() >= 60 || rnd.IntN(3) == 0) {
+			i := rnd.IntN(len(added))
+			item := added[i]
+			if !qf.Delete(item) {
+				t.Fatalf("шаг %d: Delete добавленного элемента вернул false", step)
+			}
+			f := qf.fingerprint(item)
+			if want[f]--; want[f] == 0 {
+				delete(want, f)
+			}
+			added = slices.Delete(added, i, i+1)
+		} else {
+			item := []byte(fmt.Sprintf("item-%d", rnd.IntN(200)))
+			if err := qf.Insert(item); err != nil {
+				t.Fatalf("шаг %d: %v", step, err)
+			}
+			want[qf.fingerprint(item)]++
+			added = append(added, item)
+		}
+		if step%97 == 0 {
+			checkQuotientFilter(t, qf, want)
+		}
+	}
+	checkQuotientFilter(t, qf, want)
+	for _, item := range added {
+		if !qf.Contains(item) {
+			t.Fatalf("%s не найден", item)
+		}
+	}
+}
+
+func TestQuotientFilterFull(t *testing.T) {
+	qf, _ := NewQuotientFilter(3, 8)
+	for i := 0; i < 7; i++ {
+		if err := qf.Insert([]byte{byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := qf.Insert([]byte("x")); err != ErrQuotientFull {
+		t.Fatalf("Insert в полный фильтр: %v", err)
+	}
+	if err := qf.Resize(); err != nil {
+		t.Fatal(err)
+	}
+	if err := qf.Insert([]byte("x")); err != nil {
+		t.Fatalf("Insert после Resize: %v", err)
+	}
+}
+
+func TestQuotientFilterResizeAndMerge(t *testing.T) {
+	const n = 20_000
+	a, _ := NewQuotientFilterWithRate(n, 0.01)
+	b, _ := NewQuotientFilterWithRate(n, 0.01)
+	for i := 0; i < n/2; i++ {
+		a.Insert([]byte(fmt.Sprintf("a-%d", i)))
+		b.Insert([]byte(fmt.Sprintf("b-%d", i)))
+	}
+	fps := a.fingerprints()
+	if err := a.Resize(); err != nil {
+		t.Fatal(err)
+	}
+	if a.qbits != b.qbits+1 || !slices.Equal(a.fingerprints(), fps) {
+		t.Fatal("Resize изменил отпечатки")
+	}
+	if err := a.Merge(b); err != ErrQuotientIncompatible {
+		t.Fatalf("Merge разных размеров: %v", err)
+	}
+	if err := b.Resize(); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n/2; i++ {
+		for _, p := range []string{"a", "b"} {
+			if item := []byte(fmt.Sprintf("%s-%d", p, i)); !a.Contains(item) {
+				t.Fatalf("%s не найден после Merge", item)
+			}
+		}
+	}
+
+	falsePositives := 0
+	for i := 0; i < n; i++ {
+		if a.Contains([]byte(fmt.Sprintf("missing-%d", i))) {
+			falsePositives++
+		}
+	}
+	if got, want := float64(falsePositives)/n, a.FalsePositiveRate(); got > want*1.25+0.001 {
+		t.Errorf("ошибка %.4f, теоретическая %.4f", got, want)
+	}
+}
+
+func TestQuotientFilterMergeIncompatible(t *testing.T) {
+	base, _ := NewQuotientFilter(10, 8)
+	base.Insert([]byte("a"))
+	xx, _ := NewQuotientFilter(10, 8)
+	xx.SetHasher(XXHash)
+	shortRem, _ := NewQuotientFilter(10, 6)
+	for name, other := range map[string]*QuotientFilter{"остаток": shortRem, "хэшер": xx} {
+		if err := base.Merge(other); !errors.Is(err, ErrQuotientIncompatible) {
+			t.Errorf("%s: %v, want ErrQuotientIncompatible", name, err)
+		}
+	}
+	if base.Count() != 1 || !base.Contains([]byte("a")) {
+		t.Error("неудачный Merge изменил фильтр")
+	}
+}
+
+func BenchmarkQuotientFilterInsert(b *testing.B) {
+	qf, _ := NewQuotientFilterWithRate(benchFilterItems, 0.01)
+	keys := benchKeys()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if qf.Count()+2 > int(qf.size*3/4) {
+			b.StopTimer()
+			clear(qf.data)
+			qf.count = 0
+			b.StartTimer()
+		}
+		qf.Insert(keys[i&(len(keys)-1)])
+	}
+}
+
+func BenchmarkQuotientFilterContains(b *testing.B) {
+	qf, _ := NewQuotientFilterWithRate(benchFilterItems, 0.01)
+	keys := benchKeys()
+	for _, k := range keys {
+		qf.Insert(k)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		qf.Contains(keys[i&(len(keys)-1)])
+	}
+}
@@ -0,0 +1,33 @@
+package sketch
+
+import "math/rand"
+
+type Reservoir struct {
+	k      int
+	count  int64
+	sample []int
+}
+
+func NReservoir(k int) *Reservoir {
+	return &Reservoir{
+		k:      k,
+		count:  0,
+		sample: make([]int, 0, k),
+	}
+}
+
+func (r *Reservoir) Add(x int) {
+	r.count++
+	if len(r.sample) < r.k {
+		r.sample = append(r.sample, x)
+		return
+	}
+	j := rand.Int63n(r.count)
+	if j < int64(r.k) {
+		r.sample[j] = x
+	}
+}
+
+func (r *Reservoir) Sample() []int {
+	return r.sample
+}
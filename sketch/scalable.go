@@ -0,0 +1,136 @@
+package sketch
+
+const (
+	// DefaultGrowth — во сколько раз каждая следующая стадия больше предыдущей
+	DefaultGrowth = 2
+	// DefaultTighteningRatio — во сколько раз ужесточается ошибка каждой стадии
+	DefaultTighteningRatio = 0.85
+)
+
+// ScalableBloomFilter — масштабируемый фильтр Блума (Almeida и др., 2007).
+// Хранит цепочку обычных фильтров: когда текущая стадия заполняется,
+// добавляется новая, в growth раз больше и с ошибкой в ratio раз меньше.
+// Общая вероятность ложного срабатывания не превышает
+//
+//	p0 + p0*r + p0*r^2 + ... = p0 / (1 - r) = p
+//
+// сколько бы элементов ни было добавлено
+type ScalableBloomFilter struct {
+	filters []*BloomFilter // стадии, последняя — текущая
+	counts  []int          // сколько элементов добавлено в каждую стадию
+	caps    []int          // на сколько элементов рассчитана каждая стадия
+
+	rate   float64 // ошибка следующей стадии
+	ratio  float64 // коэффициент ужесточения ошибки r
+	growth int     // коэффициент роста ёмкости s
+	hasher Hasher  // хэш-функция всех стадий
+}
+
+// NewScalableBloomFilter создаёт фильтр с первой стадией на n элементов
+// и общей вероятностью ложного срабатывания p
+func NewScalableBloomFilter(n int, p float64) (*ScalableBloomFilter, error) {
+	return NewScalableBloomFilterWithRatio(n, p, DefaultTighteningRatio, DefaultGrowth)
+}
+
+// NewScalableBloomFilterWithRatio позволяет задать коэффициент ужесточения
+// ratio (0 < ratio < 1) и коэффициент роста growth (>= 1)
+func NewScalableBloomFilterWithRatio(n int, p, ratio float64, growth int) (*ScalableBloomFilter, error) {
+	// при p >= 1 ошибка первой стадии p*(1-ratio) ещё может оказаться
+	// меньше единицы, поэтому p проверяем отдельно
+	if !(p > 0 && p < 1) || !(ratio > 0 && ratio < 1) || growth < 1 {
+		return nil, ErrInvalidParams
+	}
+	sbf := &ScalableBloomFilter{
+		rate:   p * (1 - ratio),
+		ratio:  ratio,
+		growth: growth,
+		hasher: DefaultHasher,
+	}
+	if err := sbf.grow(n); err != nil {
+		return nil, err
+	}
+	return sbf, nil
+}
+
+// grow добавляет новую стадию на n элементов
+func (sbf *ScalableBloomFilter) grow(n int) error {
+	bf, err := NewBloomFilterWithRate(n, sbf.rate)
+	if err != nil {
+		return err
+	}
+	bf.SetHasher(sbf.hasher)
+	sbf.filters = append(sbf.filters, bf)
+	sbf.counts = append(sbf.counts, 0)
+	sbf.caps = append(sbf.caps, n)
+	sbf.rate *= sbf.ratio
+	return nil
+}
+
+// SetHasher задаёт хэш-функцию всех стадий. Вызывать до добавления элементов
+func (sbf *ScalableBloomFilter) SetHasher(h Hasher) {
+	sbf.hasher = h
+	for _, bf := range sbf.filters {
+		bf.SetHasher(h)
+	}
+}
+
+// Add добавляет элемент в текущую стадию. Элементы, которые фильтр уже
+// считает присутствующими, не добавляются, чтобы повторы не занимали ёмкость
+func (sbf *ScalableBloomFilter) Add(item []byte) error {
+	if sbf.Nalich(item) {
+		return nil
+	}
+	last := len(sbf.filters) - 1
+	if sbf.counts[last] >= sbf.caps[last] {
+		if err := sbf.grow(sbf.caps[last] * sbf.growth); err != nil {
+			return err
+		}
+		last++
+	}
+	sbf.filters[last].Add(item)
+	sbf.counts[last]++
+	return nil
+}
+
+// Nalich проверяет все стадии, начиная с самой новой
+func (sbf *ScalableBloomFilter) Nalich(item []byte) bool {
+	for i := len(sbf.filters) - 1; i >= 0; i-- {
+		if sbf.filters[i].Nalich(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// Count возвращает количество добавленных элементов
+func (sbf *ScalableBloomFilter) Count() int {
+	total := 0
+	for _, c := range sbf.counts {
+		total += c
+	}
+	return total
+}
+
+// Stages возвращает количество стадий
+func (sbf *ScalableBloomFilter) Stages() int {
+	return len(sbf.filters)
+}
+
+// FalsePositiveRate возвращает теоретическую вероятность ложного
+// срабатывания при текущем заполнении: 1 - (1-p1)(1-p2)...
+func (sbf *ScalableBloomFilter) FalsePositiveRate() float64 {
+	miss := 1.0
+	for i, bf := range sbf.filters {
+		miss *= 1 - bf.FalsePositiveRate(sbf.counts[i])
+	}
+	return 1 - miss
+}
+
+// Memory возвращает суммарный размер всех стадий в байтах
+func (sbf *ScalableBloomFilter) Memory() int {
+	total := 0
+	for _, bf := range sbf.filters {
+		total += bf.Memory()
+	}
+	return total
+}
@@ -0,0 +1,64 @@
+package sketch
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestScalableBloomFilterGrowth(t *testing.T) {
+	const n0, n, p = 1000, 30_000, 0.01
+	sbf, err := NewScalableBloomFilter(n0, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fpr := measureFPR(func(b []byte) {
+		if err := sbf.Add(b); err != nil {
+			t.Fatal(err)
+		}
+	}, sbf.Nalich, n, 200_000)
+
+	// стадии на 1000, 2000, 4000, 8000 и 16000 элементов
+	if sbf.Stages() != 5 {
+		t.Errorf("Stages = %d, want 5", sbf.Stages())
+	}
+	// элементы, которые фильтр уже считал присутствующими, не считаются
+	if c := sbf.Count(); c > n || c < n*(1-p) {
+		t.Errorf("Count = %d, want около %d", c, n)
+	}
+	if fpr > p {
+		t.Errorf("доля ложных срабатываний %.4f больше заданной %.4f", fpr, p)
+	}
+	if est := sbf.FalsePositiveRate(); est > p || math.Abs(est-fpr) > 0.005 {
+		t.Errorf("FalsePositiveRate = %.4f, измерено %.4f", est, fpr)
+	}
+	for i := 0; i < n; i++ {
+		if !sbf.Nalich([]byte(fmt.Sprintf("element-%d", i))) {
+			t.Fatalf("ложноотрицательный ответ для element-%d", i)
+		}
+	}
+}
+
+func TestScalableBloomFilterParams(t *testing.T) {
+	tests := []struct {
+		n      int
+		p      float64
+		ratio  float64
+		growth int
+	}{
+		{1000, 2, 0.85, 2},
+		{1000, 1, 0.85, 2},
+		{1000, 0, 0.85, 2},
+		{1000, -0.1, 0.85, 2},
+		{1000, math.NaN(), 0.85, 2},
+		{1000, 0.01, 1, 2},
+		{1000, 0.01, 0, 2},
+		{1000, 0.01, 0.85, 0},
+		{0, 0.01, 0.85, 2},
+	}
+	for _, tt := range tests {
+		if _, err := NewScalableBloomFilterWithRatio(tt.n, tt.p, tt.ratio, tt.growth); err != ErrInvalidParams {
+			t.Errorf("%+v: %v, want ErrInvalidParams", tt, err)
+		}
+	}
+}
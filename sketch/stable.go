@@ -0,0 +1,110 @@
+package sketch
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// StableBloomFilter — стабильный фильтр Блума (Deng, Rafiei, 2006) для поиска
+// дубликатов в бесконечном потоке. Вместо битов хранит ячейки по cellBits бит.
+// Перед каждой вставкой decrement случайных ячеек уменьшаются на 1, затем k
+// ячеек элемента выставляются в максимум. Старые элементы постепенно
+// «забываются», и доля ненулевых ячеек сходится к постоянной, поэтому
+// вероятность ложного срабатывания не растёт до 100%, как у BloomFilter.
+// Платой являются ложные отрицания для давно добавленных элементов
+type StableBloomFilter struct {
+	cells     []uint64 // ячейки по cellBits бит, ячейки не пересекают границы слов
+	size      int      // количество ячеек (m)
+	cellBits  int      // ширина ячейки (d)
+	perWord   int      // ячеек в одном uint64
+	max       uint64   // максимальное значение ячейки 2^d - 1
+	hashCount int      // количество хэш-функций (k)
+	decrement int      // сколько ячеек уменьшается при каждой вставке (P)
+
+	hasher Hasher
+	rnd    *rand.Rand
+}
+
+// NewStableBloomFilter создаёт фильтр из size ячеек шириной cellBits (1..8)
+// бит, с hashCount хэш-функциями и decrement уменьшениями на вставку.
+// Одинаковый seed даёт одинаковую последовательность уменьшений
+func NewStableBloomFilter(size, cellBits, hashCount, decrement int, seed uint64) (*StableBloomFilter, error) {
+	if size <= 0 || cellBits < 1 || cellBits > 8 || hashCount < 1 || decrement < 1 {
+		return nil, ErrInvalidParams
+	}
+	perWord := 64 / cellBits
+	return &StableBloomFilter{
+		cells:     make([]uint64, (size+perWord-1)/perWord),
+		size:      size,
+		cellBits:  cellBits,
+		perWord:   perWord,
+		max:       1<<cellBits - 1,
+		hashCount: hashCount,
+		decrement: decrement,
+		hasher:    DefaultHasher,
+		rnd:       rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)),
+	}, nil
+}
+
+// SetHasher задаёт хэш-функцию. Вызывать до добавления элементов
+func (sf *StableBloomFilter) SetHasher(h Hasher) {
+	sf.hasher = h
+}
+
+// get возвращает значение ячейки idx
+func (sf *StableBloomFilter) get(idx int) uint64 {
+	shift := idx % sf.perWord * sf.cellBits
+	return sf.cells[idx/sf.perWord] >> shift & sf.max
+}
+
+// set записывает значение v в ячейку idx
+func (sf *StableBloomFilter) set(idx int, v uint64) {
+	shift := idx % sf.perWord * sf.cellBits
+	w := &sf.cells[idx/sf.perWord]
+	*w = *w&^(sf.max<<shift) | v<<shift
+}
+
+func (sf *StableBloomFilter) Add(item []byte) {
+	// уменьшаем decrement ячеек подряд, начиная со случайной:
+	// для стабильности важна лишь равномерность выбора начала
+	start := sf.rnd.IntN(sf.size)
+	for i := 0; i < sf.decrement; i++ {
+		idx := (start + i) % sf.size
+		if v := sf.get(idx); v > 0 {
+			sf.set(idx, v-1)
+		}
+	}
+
+	v1, v2 := sf.hasher.Sum128(item)
+	for i := 0; i < sf.hashCount; i++ {
+		sf.set(location(v1, v2, i, sf.size), sf.max)
+	}
+}
+
+func (sf *StableBloomFilter) Nalich(item []byte) bool {
+	v1, v2 := sf.hasher.Sum128(item)
+	for i := 0; i < sf.hashCount; i++ {
+		if sf.get(location(v1, v2, i, sf.size)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// StableFalsePositiveRate возвращает теоретическую вероятность ложного
+// срабатывания в стабильной точке, к которой фильтр сходится на длинном
+// потоке. Доля нулевых ячеек в ней
+//
+//	z = (1 / (1 + 1/(P*(1/k - 1/m))))^Max
+//
+// и ошибка равна (1 - z)^k
+func (sf *StableBloomFilter) StableFalsePositiveRate() float64 {
+	k, m := float64(sf.hashCount), float64(sf.size)
+	zeros := math.Pow(1/(1+1/(float64(sf.decrement)*(1/k-1/m))), float64(sf.max))
+	return math.Pow(1-zeros, k)
+}
+
+// Memory возвращает размер массива ячеек в байтах
+func (sf *StableBloomFilter) Memory() int {
+	return len(sf.cells) * 8
+}
@@ -0,0 +1,66 @@
+package sketch
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestStableBloomFilterParams(t *testing.T) {
+	tests := []struct{ size, cellBits, hashCount, decrement int }{
+		{0, 2, 3, 10},
+		{1000, 0, 3, 10},
+		{1000, 9, 3, 10},
+		{1000, 2, 0, 10},
+		{1000, 2, 3, 0},
+		{1000, 2, 3, -1},
+	}
+	for _, tt := range tests {
+		if _, err := NewStableBloomFilter(tt.size, tt.cellBits, tt.hashCount, tt.decrement, 1); err != ErrInvalidParams {
+			t.Errorf("%+v: %v, want ErrInvalidParams", tt, err)
+		}
+	}
+}
+
+func TestStableBloomFilterReproducible(t *testing.T) {
+	a, _ := NewStableBloomFilter(10_000, 3, 3, 10, 42)
+	b, _ := NewStableBloomFilter(10_000, 3, 3, 10, 42)
+	c, _ := NewStableBloomFilter(10_000, 3, 3, 10, 43)
+	for i := 0; i < 20_000; i++ {
+		item := []byte(fmt.Sprintf("element-%d", i))
+		a.Add(item)
+		b.Add(item)
+		c.Add(item)
+	}
+	if !slices.Equal(a.cells, b.cells) {
+		t.Error("фильтры с одинаковым seed разошлись")
+	}
+	if slices.Equal(a.cells, c.cells) {
+		t.Error("фильтры с разным seed совпали")
+	}
+}
+
+func TestStableBloomFilterStablePoint(t *testing.T) {
+	const stream, probes = 1_000_000, 100_000
+	sf, _ := NewStableBloomFilter(100_000, 3, 3, 10, 7)
+	for i := 0; i < stream; i++ {
+		sf.Add([]byte(fmt.Sprintf("element-%d", i)))
+	}
+	fp := 0
+	for i := 0; i < probes; i++ {
+		if sf.Nalich([]byte(fmt.Sprintf("missing-%d", i))) {
+			fp++
+		}
+	}
+	got, want := float64(fp)/probes, sf.StableFalsePositiveRate()
+	if math.Abs(got-want) > 0.15*want {
+		t.Errorf("доля ложных срабатываний %.4f, в стабильной точке %.4f", got, want)
+	}
+	// недавние элементы ещё не забыты
+	for i := stream - 100; i < stream; i++ {
+		if !sf.Nalich([]byte(fmt.Sprintf("element-%d", i))) {
+			t.Fatalf("забыт недавний element-%d", i)
+		}
+	}
+}
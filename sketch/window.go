@@ -0,0 +1,112 @@
+package sketch
+
+import "time"
+
+// WindowBloomFilter — фильтр Блума со скользящим окном: помнит только
+// элементы, добавленные за последние generations интервалов. Держит кольцо
+// обычных фильтров-поколений; по истечении интервала (по времени или по
+// числу добавлений) самое старое поколение очищается и становится текущим.
+//
+// Элемент, добавленный в текущее поколение, остаётся видимым от
+// (generations-1) до generations интервалов
+type WindowBloomFilter struct {
+	generations []*BloomFilter
+	current     int // индекс текущего поколения
+	added       int // добавлений в текущее поколение
+
+	interval  time.Duration // ротация по времени, 0 — выключена
+	count     int           // ротация после count добавлений, 0 — выключена
+	rotatedAt time.Time     // начало текущего интервала
+	clock     func() time.Time
+}
+
+// NewWindowBloomFilter создаёт фильтр из generations поколений, каждое
+// рассчитано на capacity элементов с ошибкой p. Поколения меняются каждые
+// interval и/или каждые count добавлений; хотя бы одно из них должно быть
+// больше нуля. Общая ошибка не превышает 1 - (1-p)^generations
+func NewWindowBloomFilter(generations, capacity int, p float64, interval time.Duration, count int) (*WindowBloomFilter, error) {
+	if generations < 1 || interval < 0 || count < 0 || (interval == 0 && count == 0) {
+		return nil, ErrInvalidParams
+	}
+	wf := &WindowBloomFilter{
+		generations: make([]*BloomFilter, generations),
+		interval:    interval,
+		count:       count,
+		clock:       time.Now,
+	}
+	for i := range wf.generations {
+		bf, err := NewBloomFilterWithRate(capacity, p)
+		if err != nil {
+			return nil, err
+		}
+		wf.generations[i] = bf
+	}
+	wf.rotatedAt = wf.clock()
+	return wf, nil
+}
+
+// SetClock подменяет источник времени, например в тестах.
+// Текущий интервал начинается заново с clock()
+func (wf *WindowBloomFilter) SetClock(clock func() time.Time) {
+	wf.clock = clock
+	wf.rotatedAt = clock()
+}
+
+// SetHasher задаёт хэш-функцию всех поколений. Вызывать до добавления элементов
+func (wf *WindowBloomFilter) SetHasher(h Hasher) {
+	for _, bf := range wf.generations {
+		bf.SetHasher(h)
+	}
+}
+
+// rotate очищает самое старое поколение и делает его текущим
+func (wf *WindowBloomFilter) rotate() {
+	wf.current = (wf.current + 1) % len(wf.generations)
+	clear(wf.generations[wf.current].bitSet)
+	wf.added = 0
+}
+
+// expire проводит ротации за все интервалы, прошедшие с прошлого вызова
+func (wf *WindowBloomFilter) expire() {
+	if wf.interval == 0 {
+		return
+	}
+	steps := wf.clock().Sub(wf.rotatedAt) / wf.interval
+	if steps <= 0 {
+		return
+	}
+	wf.rotatedAt = wf.rotatedAt.Add(steps * wf.interval)
+	// больше len(generations) ротаций подряд ничего не меняют
+	for i := 0; i < int(min(steps, time.Duration(len(wf.generations)))); i++ {
+		wf.rotate()
+	}
+}
+
+func (wf *WindowBloomFilter) Add(item []byte) {
+	wf.expire()
+	if wf.count > 0 && wf.added >= wf.count {
+		wf.rotate()
+	}
+	wf.generations[wf.current].Add(item)
+	wf.added++
+}
+
+// Nalich возвращает true, если элемент добавлялся в пределах окна
+func (wf *WindowBloomFilter) Nalich(item []byte) bool {
+	wf.expire()
+	for _, bf := range wf.generations {
+		if bf.Nalich(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// Memory возвращает суммарный размер поколений в байтах
+func (wf *WindowBloomFilter) Memory() int {
+	total := 0
+	for _, bf := range wf.generations {
+		total += bf.Memory()
+	}
+	return total
+}
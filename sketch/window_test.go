@@ -0,0 +1,59 @@
+package sketch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowBloomFilterTimeRotation(t *testing.T) {
+	wf, err := NewWindowBloomFilter(3, 1000, 0.001, time.Minute, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	wf.SetClock(func() time.Time { return now })
+
+	wf.Add([]byte("a"))
+	now = now.Add(90 * time.Second) // второе поколение
+	wf.Add([]byte("b"))
+
+	now = now.Add(time.Minute) // третье поколение: a и b ещё в окне
+	if !wf.Nalich([]byte("a")) || !wf.Nalich([]byte("b")) {
+		t.Fatal("элементы пропали раньше времени")
+	}
+
+	now = now.Add(time.Minute) // поколение с a очищено
+	if wf.Nalich([]byte("a")) {
+		t.Error("a должен был выйти из окна")
+	}
+	if !wf.Nalich([]byte("b")) {
+		t.Error("b ещё должен быть в окне")
+	}
+
+	now = now.Add(10 * time.Minute) // окно целиком прошло
+	if wf.Nalich([]byte("b")) {
+		t.Error("b должен был выйти из окна")
+	}
+}
+
+func TestWindowBloomFilterCountRotation(t *testing.T) {
+	wf, err := NewWindowBloomFilter(2, 100, 0.001, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"a", "b", "c", "d"} {
+		wf.Add([]byte(s))
+	}
+	if !wf.Nalich([]byte("a")) {
+		t.Fatal("a ещё должен быть в окне")
+	}
+	wf.Add([]byte("e")) // третье поколение вытесняет a и b
+	if wf.Nalich([]byte("a")) || wf.Nalich([]byte("b")) {
+		t.Error("a и b должны были выйти из окна")
+	}
+	for _, s := range []string{"c", "d", "e"} {
+		if !wf.Nalich([]byte(s)) {
+			t.Errorf("%s должен быть в окне", s)
+		}
+	}
+}
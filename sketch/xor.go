@@ -0,0 +1,271 @@
+package sketch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math/bits"
+	"slices"
+	"unsafe"
+)
+
+// xorMaxAttempts — сколько seed перебирается, прежде чем сдаться.
+// Для набора без повторов одна попытка удаётся с вероятностью ~0.8
+const xorMaxAttempts = 100
+
+// ErrXorConstruction — не удалось построить XOR-фильтр ни с одним seed
+var ErrXorConstruction = errors.New("sketch: не удалось построить XOR-фильтр")
+
+// XorFilter — статический XOR-фильтр (Graf, Lemire, 2020), строится один раз
+// по известному набору ключей и после этого не меняется. Занимает около
+// 1.23 * n отпечатков: ~9.84 бита на ключ для uint8 при ошибке 1/256,
+// тогда как фильтру Блума на ту же ошибку нужно ~11.5 бит.
+//
+// Элемент x присутствует, если
+//
+//	fp(x) == F[h0(x)] ^ F[h1(x)] ^ F[h2(x)]
+//
+// где h0, h1, h2 указывают в три разные трети массива F
+type XorFilter[T uint8 | uint16] struct {
+	fingerprints []T
+	blockLength  uint32 // длина одной трети массива
+	seed         uint64
+
+	hasher Hasher
+}
+
+// NewXor8 строит фильтр с 8-битными отпечатками (ошибка ~0.39%)
+func NewXor8(keys [][]byte) (*XorFilter[uint8], error) {
+	return newXorFilter[uint8](keys, DefaultHasher)
+}
+
+// NewXor16 строит фильтр с 16-битными отпечатками (ошибка ~0.0015%)
+func NewXor16(keys [][]byte) (*XorFilter[uint16], error) {
+	return newXorFilter[uint16](keys, DefaultHasher)
+}
+
+func newXorFilter[T uint8 | uint16](keys [][]byte, hasher Hasher) (*XorFilter[T], error) {
+	// одинаковые ключи дают одинаковые хэши, и построение никогда
+	// не удастся, поэтому повторы убираем заранее
+	hashes := make([]uint64, len(keys))
+	for i, key := range keys {
+		hashes[i], _ = hasher.Sum128(key)
+	}
+	slices.Sort(hashes)
+	hashes = slices.Compact(hashes)
+
+	capacity := 32 + uint32(1.23*float64(len(hashes)))
+	xf := &XorFilter[T]{
+		blockLength: capacity / 3,
+		hasher:      hasher,
+	}
+	xf.fingerprints = make([]T, 3*xf.blockLength)
+
+	seed := uint64(0x726b2b9d438b9d4d)
+	for attempt := 0; attempt < xorMaxAttempts; attempt++ {
+		xf.seed = splitmix64(&seed)
+		if xf.build(hashes) {
+			return xf, nil
+		}
+	}
+	return nil, ErrXorConstruction
+}
+
+// xorSlot — ячейка при построении: XOR всех попавших в неё хэшей и их число
+type xorSlot struct {
+	mask  uint64
+	count uint32
+}
+
+// xorStep — ключ, «отклеенный» от графа, и ячейка, которая ему досталась
+type xorStep struct {
+	hash  uint64
+	index uint32
+}
+
+// build пытается разместить хэши при текущем seed. Ячейка, в которую
+// попал ровно один ключ, однозначно ему принадлежит: убираем ключ
+// из остальных его ячеек и повторяем, пока есть такие ячейки
+func (xf *XorFilter[T]) build(keyHashes []uint64) bool {
+	slots := make([]xorSlot, len(xf.fingerprints))
+	for _, kh := range keyHashes {
+		h := xf.mix(kh)
+		for _, i := range xf.positions(h) {
+			slots[i].mask ^= h
+			slots[i].count++
+		}
+	}
+
+	queue := make([]uint32, 0, len(slots))
+	for i := range slots {
+		if slots[i].count == 1 {
+			queue = append(queue, uint32(i))
+		}
+	}
+
+	stack := make([]xorStep, 0, len(keyHashes))
+	for len(queue) > 0 {
+		i := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if slots[i].count != 1 {
+			continue
+		}
+		h := slots[i].mask
+		stack = append(stack, xorStep{hash: h, index: i})
+		for _, j := range xf.positions(h) {
+			slots[j].mask ^= h
+			slots[j].count--
+			if slots[j].count == 1 {
+				queue = append(queue, j)
+			}
+		}
+	}
+	if len(stack) != len(keyHashes) {
+		return false
+	}
+
+	// заполняем в обратном порядке: остальные две ячейки каждого ключа
+	// к этому моменту уже окончательны, а сама F[i] ещё равна нулю,
+	// поэтому её можно оставить в общей сумме
+	clear(xf.fingerprints)
+	for n := len(stack) - 1; n >= 0; n-- {
+		h, i := stack[n].hash, stack[n].index
+		p := xf.positions(h)
+		xf.fingerprints[i] = xorFingerprint[T](h) ^
+			xf.fingerprints[p[0]] ^ xf.fingerprints[p[1]] ^ xf.fingerprints[p[2]]
+	}
+	return true
+}
+
+// mix смешивает хэш ключа с seed текущей попытки
+func (xf *XorFilter[T]) mix(keyHash uint64) uint64 {
+	return murmurFmix(keyHash + xf.seed)
+}
+
+// positions возвращает три ячейки хэша, по одной в каждой трети массива
+func (xf *XorFilter[T]) positions(h uint64) [3]uint32 {
+	return [3]uint32{
+		reduce(uint32(h), xf.blockLength),
+		reduce(uint32(bits.RotateLeft64(h, 21)), xf.blockLength) + xf.blockLength,
+		reduce(uint32(bits.RotateLeft64(h, 42)), xf.blockLength) + 2*xf.blockLength,
+	}
+}
+
+// reduce отображает x в [0, n) умножением вместо деления
+func reduce(x, n uint32) uint32 {
+	return uint32(uint64(x) * uint64(n) >> 32)
+}
+
+func xorFingerprint[T uint8 | uint16](h uint64) T {
+	return T(h ^ h>>32)
+}
+
+func (xf *XorFilter[T]) Nalich(item []byte) bool {
+	kh, _ := xf.hasher.Sum128(item)
+	h := xf.mix(kh)
+	p := xf.positions(h)
+	return xorFingerprint[T](h) == xf.fingerprints[p[0]]^xf.fingerprints[p[1]]^xf.fingerprints[p[2]]
+}
+
+// Memory возвращает размер массива отпечатков в байтах
+func (xf *XorFilter[T]) Memory() int {
+	return len(xf.fingerprints) * int(unsafe.Sizeof(T(0)))
+}
+
+// splitmix64 — генератор последовательности seed
+func splitmix64(state *uint64) uint64 {
+	*state += 0x9e3779b97f4a7c15
+	z := *state
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
+
+// Формат файла XOR-фильтра (little-endian):
+//
+//	[0:4]   магическое число "XORF"
+//	[4]     версия формата
+//	[5]     идентификатор схемы хэширования
+//	[6]     ширина отпечатка в битах (8 или 16)
+//	[7]     зарезервировано (ноль)
+//	[8:16]  seed
+//	[16:20] blockLength
+//	[20:..] 3*blockLength отпечатков
+//	[..+4]  CRC-32C всех предыдущих байт
+const (
+	xorMagic         = "XORF"
+	xorFormatVersion = 1
+	xorHeaderSize    = 20
+)
+
+// MarshalBinary реализует encoding.BinaryMarshaler
+func (xf *XorFilter[T]) MarshalBinary() ([]byte, error) {
+	scheme, ok := hashSchemeOf(xf.hasher)
+	if !ok {
+		return nil, fmt.Errorf("%w: пользовательский Hasher нельзя сохранить", ErrUnknownHashScheme)
+	}
+	width := int(unsafe.Sizeof(T(0)))
+
+	buf := make([]byte, xorHeaderSize, xorHeaderSize+len(xf.fingerprints)*width+4)
+	copy(buf[0:4], xorMagic)
+	buf[4] = xorFormatVersion
+	buf[5] = scheme
+	buf[6] = byte(width * 8)
+	binary.LittleEndian.PutUint64(buf[8:16], xf.seed)
+	binary.LittleEndian.PutUint32(buf[16:20], xf.blockLength)
+	for _, fp := range xf.fingerprints {
+		if width == 1 {
+			buf = append(buf, byte(fp))
+		} else {
+			buf = binary.LittleEndian.AppendUint16(buf, uint16(fp))
+		}
+	}
+	return binary.LittleEndian.AppendUint32(buf, crc32.Checksum(buf, castagnoli)), nil
+}
+
+// UnmarshalBinary реализует encoding.BinaryUnmarshaler
+func (xf *XorFilter[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < xorHeaderSize+4 {
+		return fmt.Errorf("%w: данные обрезаны", ErrCorrupt)
+	}
+	if !bytes.Equal(data[0:4], []byte(xorMagic)) {
+		return ErrBadMagic
+	}
+	if data[4] != xorFormatVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedFormat, data[4])
+	}
+	hasher, ok := hasherOf(data[5])
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrUnknownHashScheme, data[5])
+	}
+	width := int(unsafe.Sizeof(T(0)))
+	if int(data[6]) != width*8 {
+		return fmt.Errorf("%w: отпечатки по %d бит, а фильтр на %d", ErrUnsupportedFormat, data[6], width*8)
+	}
+	blockLength := binary.LittleEndian.Uint32(data[16:20])
+	// пустой массив отпечатков конструктор не создаёт, а Nalich на нём упадёт
+	if blockLength == 0 || data[7] != 0 || len(data) != xorHeaderSize+3*int(blockLength)*width+4 {
+		return ErrCorrupt
+	}
+	body := data[:len(data)-4]
+	if crc32.Checksum(body, castagnoli) != binary.LittleEndian.Uint32(data[len(body):]) {
+		return ErrChecksum
+	}
+
+	fingerprints := make([]T, 3*blockLength)
+	for i := range fingerprints {
+		if width == 1 {
+			fingerprints[i] = T(body[xorHeaderSize+i])
+		} else {
+			fingerprints[i] = T(binary.LittleEndian.Uint16(body[xorHeaderSize+2*i:]))
+		}
+	}
+
+	xf.fingerprints = fingerprints
+	xf.blockLength = blockLength
+	xf.seed = binary.LittleEndian.Uint64(data[8:16])
+	xf.hasher = hasher
+	return nil
+}
@@ -0,0 +1,182 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"testing"
+)
+
+func xorKeys(prefix string, n int) [][]byte {
+	keys := make([][]byte, n)
+	for i := range keys {
+		keys[i] = []byte(fmt.Sprintf("%s-%d", prefix, i))
+	}
+	return keys
+}
+
+func TestXorFilterMembership(t *testing.T) {
+	const n, probes = 100_000, 200_000
+	keys := xorKeys("element", n)
+	x8, err := NewXor8(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x16, err := NewXor16(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		if !x8.Nalich(key) || !x16.Nalich(key) {
+			t.Fatalf("ложноотрицательный ответ для %s", key)
+		}
+	}
+	fp8, fp16 := 0, 0
+	for _, key := range xorKeys("missing", probes) {
+		if x8.Nalich(key) {
+			fp8++
+		}
+		if x16.Nalich(key) {
+			fp16++
+		}
+	}
+	if rate := float64(fp8) / probes; rate > 1.5/256 {
+		t.Errorf("Xor8: доля ложных срабатываний %.5f, ожидается ~%.5f", rate, 1.0/256)
+	}
+	if rate := float64(fp16) / probes; rate > 20.0/65536 {
+		t.Errorf("Xor16: доля ложных срабатываний %.6f", rate)
+	}
+	if bits := float64(x8.Memory()*8) / n; bits > 10 {
+		t.Errorf("Xor8 занимает %.2f бита на ключ", bits)
+	}
+}
+
+func TestXorFilterDuplicatesAndEmpty(t *testing.T) {
+	keys := append(xorKeys("element", 1000), xorKeys("element", 500)...)
+	xf, err := NewXor8(keys)
+	if err != nil {
+		t.Fatalf("повторы ключей: %v", err)
+	}
+	for _, key := range keys {
+		if !xf.Nalich(key) {
+			t.Fatalf("ложноотрицательный ответ для %s", key)
+		}
+	}
+
+	empty, err := NewXor8(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp := 0
+	for _, key := range xorKeys("missing", 1000) {
+		if empty.Nalich(key) {
+			fp++
+		}
+	}
+	// в пустом фильтре все отпечатки нулевые, совпадает только нулевой отпечаток
+	if fp > 20 {
+		t.Errorf("пустой фильтр: %d ложных срабатываний из 1000", fp)
+	}
+}
+
+func TestXorFilterSeedRetry(t *testing.T) {
+	// для маленьких наборов первая попытка часто не удаётся; фильтры,
+	// построенные со второго и следующих seed, должны работать так же
+	state := uint64(0x726b2b9d438b9d4d)
+	first := splitmix64(&state)
+	retried := 0
+	for set := 0; set < 200; set++ {
+		keys := xorKeys(fmt.Sprintf("set%d", set), 20)
+		xf, err := NewXor8(keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if xf.seed != first {
+			retried++
+		}
+		for _, key := range keys {
+			if !xf.Nalich(key) {
+				t.Fatalf("набор %d: ложноотрицательный ответ после смены seed", set)
+			}
+		}
+	}
+	if retried == 0 {
+		t.Error("ни один набор не потребовал повторной попытки")
+	}
+
+	// повторяющийся хэш нельзя разместить ни при каком seed
+	xf := &XorFilter[uint8]{blockLength: 11, fingerprints: make([]uint8, 33)}
+	if xf.build([]uint64{42, 42}) {
+		t.Error("build разместил два одинаковых хэша")
+	}
+}
+
+func TestXorFilterBinary(t *testing.T) {
+	keys := xorKeys("element", 5000)
+	x8, _ := NewXor8(keys)
+	x16, _ := NewXor16(keys)
+
+	data8, err := x8.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data16, err := x16.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back8 XorFilter[uint8]
+	var back16 XorFilter[uint16]
+	if err := back8.UnmarshalBinary(data8); err != nil {
+		t.Fatal(err)
+	}
+	if err := back16.UnmarshalBinary(data16); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		if !back8.Nalich(key) || !back16.Nalich(key) {
+			t.Fatalf("ложноотрицательный ответ после чтения для %s", key)
+		}
+	}
+	if back8.seed != x8.seed || len(back8.fingerprints) != len(x8.fingerprints) {
+		t.Error("Xor8 после UnmarshalBinary отличается")
+	}
+
+	with := func(i int, b byte) []byte {
+		d := append([]byte(nil), data8...)
+		d[i] = b
+		return d
+	}
+	// заголовок без отпечатков с верной контрольной суммой
+	empty := append([]byte(nil), data8[:xorHeaderSize]...)
+	binary.LittleEndian.PutUint32(empty[16:20], 0)
+	empty = binary.LittleEndian.AppendUint32(empty, crc32.Checksum(empty, castagnoli))
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"магическое число", with(0, 'Y'), ErrBadMagic},
+		{"версия", with(4, 9), ErrUnsupportedFormat},
+		{"схема", with(5, 200), ErrUnknownHashScheme},
+		{"испорченный отпечаток", with(xorHeaderSize+7, data8[xorHeaderSize+7]^1), ErrChecksum},
+		{"обрезано", data8[:len(data8)-1], ErrCorrupt},
+		{"лишний байт", append(append([]byte(nil), data8...), 0), ErrCorrupt},
+		{"короткий", data8[:10], ErrCorrupt},
+		{"нет отпечатков", empty, ErrCorrupt},
+	}
+	for _, tt := range tests {
+		var xf XorFilter[uint8]
+		if err := xf.UnmarshalBinary(tt.data); !errors.Is(err, tt.want) {
+			t.Errorf("%s: %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	// 16-битный образ нельзя прочитать в 8-битный фильтр и наоборот
+	if err := back8.UnmarshalBinary(data16); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Xor16 в Xor8: %v", err)
+	}
+	if err := back16.UnmarshalBinary(data8); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Xor8 в Xor16: %v", err)
+	}
+}